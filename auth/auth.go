@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/s4lat/gokan/database"
+)
+
+var (
+	// ErrInvalidCredentials - returned when login or password is wrong.
+	ErrInvalidCredentials = errors.New("invalid login or password")
+	// ErrUsernameTaken - returned on registration or update with username of other person.
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrEmailTaken - returned on registration or update with email of other person.
+	ErrEmailTaken = errors.New("email is already taken")
+)
+
+// dummyHash - used for password check when person is not found, so response time
+// doesn't tell that login doesn't exist.
+const dummyHash = "$2a$10$55SfJ0k19bPwVURzCYsWKevF9oaIMwf73v7f9eHgDb7n28GeofcFK"
+
+// RegisterForm - data required for person registration.
+type RegisterForm struct {
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+}
+
+// Validate - checks username, email and password of the form.
+func (f RegisterForm) Validate() error {
+	if err := ValidateUsername(f.Username); err != nil {
+		return err
+	}
+
+	if err := ValidateEmail(f.Email); err != nil {
+		return err
+	}
+
+	if err := ValidatePassword(f.Password); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(f.Password, f.Username) {
+		return ValidationError{Field: "password", Message: "must not be equal to username"}
+	}
+	return nil
+}
+
+// Register - validates form and creates person with hashed password.
+// Returns ErrUsernameTaken or ErrEmailTaken if person with same username or email exists.
+func Register(ctx context.Context, persons database.PersonManager, form RegisterForm) (database.Person, error) {
+	if err := form.Validate(); err != nil {
+		return database.Person{}, err
+	}
+
+	hash, err := HashPassword(form.Password)
+	if err != nil {
+		return database.Person{}, fmt.Errorf("Register() -> %w", err)
+	}
+
+	person, err := persons.Create(ctx, database.Person{
+		Username:     form.Username,
+		FirstName:    form.FirstName,
+		LastName:     form.LastName,
+		Email:        form.Email,
+		PasswordHash: hash,
+	})
+	if takenErr := takenError(err); takenErr != nil {
+		return database.Person{}, takenErr
+	}
+
+	if err != nil {
+		return database.Person{}, fmt.Errorf("Register() -> %w", err)
+	}
+	return person, nil
+}
+
+// UpdatePerson - validates changed username and email, and updates person with personID.
+// Returns ErrUsernameTaken or ErrEmailTaken if other person has same username or email.
+func UpdatePerson(ctx context.Context, persons database.PersonManager, personID uint32,
+	update database.PersonUpdate) (database.Person, error) {
+	if update.Username != nil {
+		if err := ValidateUsername(*update.Username); err != nil {
+			return database.Person{}, err
+		}
+	}
+
+	if update.Email != nil {
+		if err := ValidateEmail(*update.Email); err != nil {
+			return database.Person{}, err
+		}
+	}
+
+	person, err := persons.Update(ctx, personID, update)
+	if takenErr := takenError(err); takenErr != nil {
+		return database.Person{}, takenErr
+	}
+
+	if err != nil {
+		return database.Person{}, fmt.Errorf("UpdatePerson() -> %w", err)
+	}
+	return person, nil
+}
+
+// ChangePassword - checks current password of person with personID and replaces it with newPassword.
+// All sessions of person are revoked, so person has to log in again everywhere.
+func ChangePassword(ctx context.Context, persons database.PersonManager, personID uint32,
+	currentPassword, newPassword string) error {
+	person, err := persons.GetByID(ctx, personID, database.WithoutRelations())
+	if err != nil {
+		return fmt.Errorf("ChangePassword() -> %w", err)
+	}
+
+	err = CheckPassword(person.PasswordHash, currentPassword)
+	if errors.Is(err, ErrInvalidCredentials) {
+		return ValidationError{Field: "current_password", Message: "is wrong"}
+	}
+	if err != nil {
+		return fmt.Errorf("ChangePassword() -> %w", err)
+	}
+
+	if err := ValidatePassword(newPassword); err != nil {
+		return err
+	}
+	if strings.EqualFold(newPassword, person.Username) {
+		return ValidationError{Field: "password", Message: "must not be equal to username"}
+	}
+
+	hash, err := HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("ChangePassword() -> %w", err)
+	}
+
+	if err := persons.UpdatePasswordHash(ctx, personID, hash); err != nil {
+		return fmt.Errorf("ChangePassword() -> %w", err)
+	}
+	return nil
+}
+
+// takenError - returns ErrUsernameTaken or ErrEmailTaken if err is violation of unique username or email,
+// nil otherwise.
+func takenError(err error) error {
+	var dbErr *database.Error
+	if !errors.As(err, &dbErr) || !errors.Is(dbErr, database.ErrConflict) {
+		return nil
+	}
+
+	switch dbErr.Constraint {
+	case "person_username_key":
+		return ErrUsernameTaken
+	case "person_email_key":
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+// Login - searches person by username or email and checks password.
+// Returns ErrInvalidCredentials if person is not found or password is wrong.
+func Login(ctx context.Context, persons database.PersonManager, login, password string) (database.Person, error) {
+	getPerson := persons.GetByUsername
+	if strings.Contains(login, "@") {
+		getPerson = persons.GetByEmail
+	}
+
+	person, err := getPerson(ctx, login, database.WithoutRelations())
+	if errors.Is(err, database.ErrNotFound) {
+		_ = CheckPassword(dummyHash, password)
+		return database.Person{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return database.Person{}, fmt.Errorf("Login() -> %w", err)
+	}
+
+	if err := CheckPassword(person.PasswordHash, password); err != nil {
+		return database.Person{}, err
+	}
+	return person, nil
+}
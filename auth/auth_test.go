@@ -0,0 +1,309 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/s4lat/gokan/database"
+)
+
+// fakePersons - in-memory database.PersonManager that emulates UNIQUE constraints of person table.
+type fakePersons struct {
+	persons []database.Person
+}
+
+func (fp *fakePersons) Create(_ context.Context, person database.Person) (database.Person, error) {
+	for _, p := range fp.persons {
+		if p.Username == person.Username {
+			return database.Person{}, &database.Error{Kind: database.ErrConflict, Constraint: "person_username_key"}
+		}
+		if p.Email == person.Email {
+			return database.Person{}, &database.Error{Kind: database.ErrConflict, Constraint: "person_email_key"}
+		}
+	}
+	person.ID = uint32(len(fp.persons) + 1)
+	fp.persons = append(fp.persons, person)
+	return person, nil
+}
+
+func (fp *fakePersons) Update(_ context.Context, personID uint32,
+	update database.PersonUpdate) (database.Person, error) {
+	for _, p := range fp.persons {
+		if p.ID == personID {
+			continue
+		}
+		if update.Username != nil && p.Username == *update.Username {
+			return database.Person{}, &database.Error{Kind: database.ErrConflict, Constraint: "person_username_key"}
+		}
+		if update.Email != nil && p.Email == *update.Email {
+			return database.Person{}, &database.Error{Kind: database.ErrConflict, Constraint: "person_email_key"}
+		}
+	}
+
+	for i, p := range fp.persons {
+		if p.ID != personID {
+			continue
+		}
+		if update.Username != nil {
+			fp.persons[i].Username = *update.Username
+		}
+		if update.Email != nil {
+			fp.persons[i].Email = *update.Email
+		}
+		return fp.persons[i], nil
+	}
+	return database.Person{}, database.ErrNotFound
+}
+
+func (fp *fakePersons) UpdatePasswordHash(_ context.Context, personID uint32, passwordHash string) error {
+	for i, p := range fp.persons {
+		if p.ID == personID {
+			fp.persons[i].PasswordHash = passwordHash
+			return nil
+		}
+	}
+	return database.ErrNotFound
+}
+
+func (fp *fakePersons) DeleteByID(context.Context, uint32, map[uint32]uint32) error {
+	return errors.New("not implemented")
+}
+
+func (fp *fakePersons) GetByID(_ context.Context, personID uint32, _ ...database.LoadOption) (database.Person, error) {
+	return fp.find(func(p database.Person) bool { return p.ID == personID })
+}
+
+func (fp *fakePersons) GetSmallByID(ctx context.Context, personID uint32) (database.SmallPerson, error) {
+	person, err := fp.GetByID(ctx, personID)
+	return person.Small(), err
+}
+
+func (fp *fakePersons) GetByEmail(_ context.Context, email string, _ ...database.LoadOption) (database.Person, error) {
+	return fp.find(func(p database.Person) bool { return p.Email == email })
+}
+
+func (fp *fakePersons) GetByUsername(_ context.Context, username string,
+	_ ...database.LoadOption) (database.Person, error) {
+	return fp.find(func(p database.Person) bool { return p.Username == username })
+}
+
+func (fp *fakePersons) find(match func(database.Person) bool) (database.Person, error) {
+	for _, p := range fp.persons {
+		if match(p) {
+			return p, nil
+		}
+	}
+	return database.Person{}, database.ErrNotFound
+}
+
+func TestValidatePassword(t *testing.T) {
+	cases := map[string]bool{
+		"":                       false,
+		"abc123":                 false,
+		"abcdefghij":             false,
+		"1234567890":             false,
+		"correct horse 1":        true,
+		"пароль123":              true,
+		string(make([]byte, 80)): false,
+	}
+
+	for password, isValid := range cases {
+		err := ValidatePassword(password)
+		if isValid && err != nil {
+			t.Errorf("ValidatePassword(%q) returned error for valid password: %v", password, err)
+		}
+		if !isValid && err == nil {
+			t.Errorf("ValidatePassword(%q) does't return error for weak password", password)
+		}
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	cases := map[string]bool{
+		"s4lat@mail.ru":            true,
+		"first.last@example.co.uk": true,
+		"":                         false,
+		"s4lat":                    false,
+		"s4lat@":                   false,
+		"Maxim <s4lat@mail.ru>":    false,
+	}
+
+	for email, isValid := range cases {
+		err := ValidateEmail(email)
+		if isValid && err != nil {
+			t.Errorf("ValidateEmail(%q) returned error for valid email: %v", email, err)
+		}
+		if !isValid && err == nil {
+			t.Errorf("ValidateEmail(%q) does't return error for invalid email", email)
+		}
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	cases := map[string]bool{
+		"s4lat":        true,
+		"Galaxy_Shad.": true,
+		"ab":           false,
+		"with space":   false,
+		"кириллица":    false,
+	}
+
+	for username, isValid := range cases {
+		err := ValidateUsername(username)
+		if isValid && err != nil {
+			t.Errorf("ValidateUsername(%q) returned error for valid username: %v", username, err)
+		}
+		if !isValid && err == nil {
+			t.Errorf("ValidateUsername(%q) does't return error for invalid username", username)
+		}
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hash == "secret123" {
+		t.Error("HashPassword() returned plain password")
+	}
+
+	if err := CheckPassword(hash, "secret123"); err != nil {
+		t.Errorf("CheckPassword() returned error for right password: %v", err)
+	}
+
+	if err := CheckPassword(hash, "secret124"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("CheckPassword() returned %v for wrong password, expected ErrInvalidCredentials", err)
+	}
+
+	if err := CheckPassword("null", "null"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("CheckPassword() returned %v for not bcrypt hash, expected ErrInvalidCredentials", err)
+	}
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	persons := &fakePersons{}
+
+	form := RegisterForm{Username: "s4lat", FirstName: "Maxim", LastName: "Zakazchik",
+		Email: "s4lat@mail.ru", Password: "secret123"}
+
+	person, err := Register(ctx, persons, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if person.PasswordHash == form.Password {
+		t.Error("Register() stored plain password")
+	}
+
+	duplicateUsername := form
+	duplicateUsername.Email = "other@mail.ru"
+	if _, err := Register(ctx, persons, duplicateUsername); !errors.Is(err, ErrUsernameTaken) {
+		t.Errorf("Register() returned %v for taken username, expected ErrUsernameTaken", err)
+	}
+
+	duplicateEmail := form
+	duplicateEmail.Username = "other"
+	if _, err := Register(ctx, persons, duplicateEmail); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("Register() returned %v for taken email, expected ErrEmailTaken", err)
+	}
+
+	weakPassword := form
+	weakPassword.Username, weakPassword.Email, weakPassword.Password = "other", "other@mail.ru", "123"
+	var validationErr ValidationError
+	if _, err := Register(ctx, persons, weakPassword); !errors.As(err, &validationErr) ||
+		validationErr.Field != "password" {
+		t.Errorf("Register() returned %v for weak password, expected ValidationError", err)
+	}
+
+	for _, login := range []string{form.Username, form.Email} {
+		loggedPerson, err := Login(ctx, persons, login, form.Password)
+		if err != nil {
+			t.Errorf("Login(%q) returned error for right password: %v", login, err)
+		}
+		if loggedPerson.ID != person.ID {
+			t.Errorf("Login(%q) returned wrong person: %v", login, loggedPerson)
+		}
+	}
+
+	if _, err := Login(ctx, persons, form.Username, "wrong123"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login() returned %v for wrong password, expected ErrInvalidCredentials", err)
+	}
+
+	if _, err := Login(ctx, persons, "nobody", form.Password); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login() returned %v for non-existent person, expected ErrInvalidCredentials", err)
+	}
+}
+
+func TestUpdatePerson(t *testing.T) {
+	ctx := context.Background()
+	persons := &fakePersons{}
+
+	person, err := Register(ctx, persons,
+		RegisterForm{Username: "s4lat", Email: "s4lat@mail.ru", Password: "secret123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := Register(ctx, persons, RegisterForm{Username: "other", Email: "other@mail.ru", Password: "secret123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	username, email := "s4lat_new", "new@mail.ru"
+	updated, err := UpdatePerson(ctx, persons, person.ID, database.PersonUpdate{Username: &username, Email: &email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Username != username || updated.Email != email {
+		t.Errorf("UpdatePerson() returned %v, expected updated username and email", updated)
+	}
+
+	var validationErr ValidationError
+	for _, update := range []database.PersonUpdate{{Username: new(string)}, {Email: &username}} {
+		if _, err := UpdatePerson(ctx, persons, person.ID, update); !errors.As(err, &validationErr) {
+			t.Errorf("UpdatePerson() returned %v for invalid update, expected ValidationError", err)
+		}
+	}
+
+	if _, err := UpdatePerson(ctx, persons, person.ID,
+		database.PersonUpdate{Username: &other.Username}); !errors.Is(err, ErrUsernameTaken) {
+		t.Errorf("UpdatePerson() returned %v for taken username, expected ErrUsernameTaken", err)
+	}
+	if _, err := UpdatePerson(ctx, persons, person.ID,
+		database.PersonUpdate{Email: &other.Email}); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("UpdatePerson() returned %v for taken email, expected ErrEmailTaken", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	persons := &fakePersons{}
+
+	form := RegisterForm{Username: "s4lat", Email: "s4lat@mail.ru", Password: "secret123"}
+	person, err := Register(ctx, persons, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var validationErr ValidationError
+	if err := ChangePassword(ctx, persons, person.ID, "wrong123", "newsecret123"); !errors.As(err, &validationErr) ||
+		validationErr.Field != "current_password" {
+		t.Errorf("ChangePassword() returned %v for wrong current password, expected ValidationError", err)
+	}
+	if err := ChangePassword(ctx, persons, person.ID, form.Password, "123"); !errors.As(err, &validationErr) ||
+		validationErr.Field != "password" {
+		t.Errorf("ChangePassword() returned %v for weak password, expected ValidationError", err)
+	}
+
+	if err := ChangePassword(ctx, persons, person.ID, form.Password, "newsecret123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Login(ctx, persons, form.Username, form.Password); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login() with old password returned %v, expected ErrInvalidCredentials", err)
+	}
+	if _, err := Login(ctx, persons, form.Username, "newsecret123"); err != nil {
+		t.Errorf("Login() with new password returned error: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MinPasswordLength - min length of password in characters.
+	MinPasswordLength = 8
+	// MaxPasswordLength - max length of password in bytes, bcrypt ignores bytes after 72th.
+	MaxPasswordLength = 72
+
+	minUsernameLength = 3
+	maxUsernameLength = 32
+)
+
+// ValidationError - error, returned when user provided data is invalid.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error - returns error message.
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// HashPassword - returns bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("HashPassword() -> %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword - compares password with bcrypt hash, returns ErrInvalidCredentials if they don't match.
+func CheckPassword(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) || errors.Is(err, bcrypt.ErrHashTooShort) {
+		return ErrInvalidCredentials
+	}
+	if err != nil {
+		return fmt.Errorf("CheckPassword() -> %w", err)
+	}
+	return nil
+}
+
+// ValidatePassword - checks that password is long enough and contains letters and digits.
+func ValidatePassword(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return ValidationError{Field: "password",
+			Message: fmt.Sprintf("must be at least %d characters long", MinPasswordLength)}
+	}
+
+	if len(password) > MaxPasswordLength {
+		return ValidationError{Field: "password",
+			Message: fmt.Sprintf("must be at most %d bytes long", MaxPasswordLength)}
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	if !hasLetter || !hasDigit {
+		return ValidationError{Field: "password", Message: "must contain both letters and digits"}
+	}
+	return nil
+}
+
+// ValidateEmail - checks that email is a bare address like 'user@example.com'.
+func ValidateEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return ValidationError{Field: "email", Message: "must be an address like user@example.com"}
+	}
+	return nil
+}
+
+// ValidateUsername - checks length of username and that it contains only latin letters, digits, '_', '-', '.'.
+func ValidateUsername(username string) error {
+	if len(username) < minUsernameLength || len(username) > maxUsernameLength {
+		return ValidationError{Field: "username",
+			Message: fmt.Sprintf("must be from %d to %d characters long", minUsernameLength, maxUsernameLength)}
+	}
+
+	for _, r := range username {
+		isAllowed := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') || r == '_' || r == '-' || r == '.'
+		if !isAllowed {
+			return ValidationError{Field: "username",
+				Message: "must contain only latin letters, digits, '_', '-' and '.'"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/s4lat/gokan/database"
+)
+
+// ErrForbidden - returned when person has no permission for action on board, same as database.ErrForbidden.
+var ErrForbidden = database.ErrForbidden
+
+// Permission - action on board, that requires authorization.
+type Permission int
+
+const (
+	// ReadBoard - viewing board with its columns, tasks, tags and contributors.
+	ReadBoard Permission = iota + 1
+	// EditTasks - creating, editing, moving and deleting tasks.
+	EditTasks
+	// ManageTags - creating, editing and deleting board tags.
+	ManageTags
+	// ManageColumns - creating, renaming and deleting board columns.
+	ManageColumns
+	// EditBoard - renaming board.
+	EditBoard
+	// ManageContributors - adding and removing board contributors, changing their roles.
+	ManageContributors
+	// DeleteBoard - deleting board.
+	DeleteBoard
+	// TransferOwnership - making contributor the owner of board.
+	TransferOwnership
+	// WriteComments - commenting tasks, editing and deleting own comments.
+	WriteComments
+	// ManageComments - editing and deleting comments of other persons.
+	ManageComments
+)
+
+var permissionNames = map[Permission]string{
+	ReadBoard:          "read board",
+	EditTasks:          "edit tasks",
+	ManageTags:         "manage tags",
+	ManageColumns:      "manage columns",
+	EditBoard:          "edit board",
+	ManageContributors: "manage contributors",
+	DeleteBoard:        "delete board",
+	TransferOwnership:  "transfer ownership",
+	WriteComments:      "write comments",
+	ManageComments:     "manage comments",
+}
+
+func (p Permission) String() string {
+	if name, ok := permissionNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Permission(%d)", int(p))
+}
+
+// Role - relation of person to board.
+type Role int
+
+const (
+	// RoleOutsider - person is neither owner nor contributor of board.
+	RoleOutsider Role = iota
+	// RoleViewer - person is contributor of board with database.ContributorViewer role.
+	RoleViewer
+	// RoleMember - person is contributor of board with database.ContributorMember role.
+	RoleMember
+	// RoleAdmin - person is contributor of board with database.ContributorAdmin role.
+	RoleAdmin
+	// RoleOwner - person is owner of board.
+	RoleOwner
+)
+
+// contributorRoles - roles of contributors by their role in db.
+var contributorRoles = map[database.ContributorRole]Role{
+	database.ContributorViewer: RoleViewer,
+	database.ContributorMember: RoleMember,
+	database.ContributorAdmin:  RoleAdmin,
+}
+
+// rolePermissions - permissions granted to each role, outsiders have no permissions.
+var rolePermissions = map[Role]map[Permission]bool{
+	RoleViewer: {
+		ReadBoard: true,
+	},
+	RoleMember: {
+		ReadBoard:     true,
+		EditTasks:     true,
+		ManageTags:    true,
+		ManageColumns: true,
+		WriteComments: true,
+	},
+	RoleAdmin: {
+		ReadBoard:          true,
+		EditTasks:          true,
+		ManageTags:         true,
+		ManageColumns:      true,
+		EditBoard:          true,
+		ManageContributors: true,
+		WriteComments:      true,
+		ManageComments:     true,
+	},
+	RoleOwner: {
+		ReadBoard:          true,
+		EditTasks:          true,
+		ManageTags:         true,
+		ManageColumns:      true,
+		EditBoard:          true,
+		ManageContributors: true,
+		DeleteBoard:        true,
+		TransferOwnership:  true,
+		WriteComments:      true,
+		ManageComments:     true,
+	},
+}
+
+// RoleOf - returns role of person with personID in board, board must be loaded with contributors.
+func RoleOf(board database.Board, personID uint32) Role {
+	if board.Owner.ID == personID {
+		return RoleOwner
+	}
+
+	for _, contributor := range board.Contributors {
+		if contributor.ID == personID {
+			if role, ok := contributorRoles[contributor.Role]; ok {
+				return role
+			}
+			return RoleMember
+		}
+	}
+	return RoleOutsider
+}
+
+// Can - checks if person with personID has permission in board.
+func Can(board database.Board, personID uint32, permission Permission) bool {
+	return rolePermissions[RoleOf(board, personID)][permission]
+}
+
+// Authorize - returns ErrForbidden if person with personID has no permission in board.
+func Authorize(board database.Board, personID uint32, permission Permission) error {
+	if !Can(board, personID, permission) {
+		return fmt.Errorf("%w: no permission to %s", ErrForbidden, permission)
+	}
+	return nil
+}
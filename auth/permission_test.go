@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/s4lat/gokan/database"
+)
+
+func TestRoleOf(t *testing.T) {
+	board := database.Board{
+		Owner: database.BoardOwner{ID: 1},
+		Contributors: []database.Contributor{
+			{ID: 2, Role: database.ContributorAdmin},
+			{ID: 3, Role: database.ContributorMember},
+			{ID: 4, Role: database.ContributorViewer},
+			{ID: 5},
+		},
+	}
+
+	cases := map[uint32]Role{1: RoleOwner, 2: RoleAdmin, 3: RoleMember, 4: RoleViewer, 5: RoleMember, 6: RoleOutsider}
+	for personID, expectedRole := range cases {
+		if role := RoleOf(board, personID); role != expectedRole {
+			t.Errorf("RoleOf(%d) returned %v, expected %v", personID, role, expectedRole)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	const (
+		ownerID = iota + 1
+		adminID
+		memberID
+		viewerID
+		outsiderID
+	)
+
+	board := database.Board{
+		Owner: database.BoardOwner{ID: ownerID},
+		Contributors: []database.Contributor{
+			{ID: adminID, Role: database.ContributorAdmin},
+			{ID: memberID, Role: database.ContributorMember},
+			{ID: viewerID, Role: database.ContributorViewer},
+		},
+	}
+
+	cases := map[Permission]map[uint32]bool{
+		ReadBoard:          {ownerID: true, adminID: true, memberID: true, viewerID: true, outsiderID: false},
+		EditTasks:          {ownerID: true, adminID: true, memberID: true, viewerID: false, outsiderID: false},
+		ManageTags:         {ownerID: true, adminID: true, memberID: true, viewerID: false, outsiderID: false},
+		ManageColumns:      {ownerID: true, adminID: true, memberID: true, viewerID: false, outsiderID: false},
+		EditBoard:          {ownerID: true, adminID: true, memberID: false, viewerID: false, outsiderID: false},
+		ManageContributors: {ownerID: true, adminID: true, memberID: false, viewerID: false, outsiderID: false},
+		DeleteBoard:        {ownerID: true, adminID: false, memberID: false, viewerID: false, outsiderID: false},
+		TransferOwnership:  {ownerID: true, adminID: false, memberID: false, viewerID: false, outsiderID: false},
+		WriteComments:      {ownerID: true, adminID: true, memberID: true, viewerID: false, outsiderID: false},
+		ManageComments:     {ownerID: true, adminID: true, memberID: false, viewerID: false, outsiderID: false},
+	}
+
+	for permission, allowed := range cases {
+		for personID, isAllowed := range allowed {
+			err := Authorize(board, personID, permission)
+			if isAllowed && err != nil {
+				t.Errorf("Authorize(%d, %s) returned error for allowed action: %v", personID, permission, err)
+			}
+			if !isAllowed && !errors.Is(err, ErrForbidden) {
+				t.Errorf("Authorize(%d, %s) returned %v for forbidden action, expected ErrForbidden",
+					personID, permission, err)
+			}
+		}
+	}
+}
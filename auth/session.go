@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/s4lat/gokan/database"
+)
+
+const (
+	// CookieSessionTTL - lifetime of browser session.
+	CookieSessionTTL = 7 * 24 * time.Hour
+	// TokenSessionTTL - lifetime of API token.
+	TokenSessionTTL = 90 * 24 * time.Hour
+	// MinSecretLength - min length of secret used for signing cookies.
+	MinSecretLength = 32
+
+	tokenSize = 32
+)
+
+// ErrUnauthenticated - returned when session is missing, invalid, expired or revoked.
+var ErrUnauthenticated = errors.New("authentication required")
+
+// NewToken - generates random session token, returns token for client and it's hash for db.
+func NewToken() (token, hash string, err error) {
+	buf := make([]byte, tokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("NewToken() -> %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// HashToken - returns hash of token, under which session is stored in db.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Signer - signs values with HMAC-SHA256, so client can't forge them.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner - returns Signer with secret as key, secret must be at least MinSecretLength bytes.
+func NewSigner(secret string) (Signer, error) {
+	if len(secret) < MinSecretLength {
+		return Signer{}, fmt.Errorf("NewSigner() -> secret must be at least %d bytes", MinSecretLength)
+	}
+	return Signer{key: []byte(secret)}, nil
+}
+
+// Sign - returns value with appended signature.
+func (s Signer) Sign(value string) string {
+	return value + "." + s.signature(value)
+}
+
+// Verify - checks signature of signed value and returns value without it.
+// Returns ErrUnauthenticated if signature is wrong.
+func (s Signer) Verify(signed string) (string, error) {
+	sep := strings.LastIndexByte(signed, '.')
+	if sep < 0 {
+		return "", ErrUnauthenticated
+	}
+
+	value, signature := signed[:sep], signed[sep+1:]
+	if !hmac.Equal([]byte(signature), []byte(s.signature(value))) {
+		return "", ErrUnauthenticated
+	}
+	return value, nil
+}
+
+func (s Signer) signature(value string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Sessions - starts and checks sessions of persons.
+type Sessions struct {
+	Store   database.SessionManager
+	Persons database.PersonManager
+	Signer  Signer
+}
+
+// StartCookie - creates browser session for person, returns signed value for session cookie.
+func (s Sessions) StartCookie(ctx context.Context, personID uint32) (string, database.Session, error) {
+	token, session, err := s.start(ctx, personID, database.SessionCookie, CookieSessionTTL)
+	if err != nil {
+		return "", database.Session{}, fmt.Errorf("Sessions.StartCookie() -> %w", err)
+	}
+	return s.Signer.Sign(token), session, nil
+}
+
+// IssueToken - creates API token for person, returns token for 'Authorization: Bearer' header.
+func (s Sessions) IssueToken(ctx context.Context, personID uint32) (string, database.Session, error) {
+	token, session, err := s.start(ctx, personID, database.SessionToken, TokenSessionTTL)
+	if err != nil {
+		return "", database.Session{}, fmt.Errorf("Sessions.IssueToken() -> %w", err)
+	}
+	return token, session, nil
+}
+
+// AuthenticateCookie - returns person and session by value of session cookie.
+// Returns ErrUnauthenticated if cookie is forged or session is not active.
+func (s Sessions) AuthenticateCookie(ctx context.Context,
+	cookieValue string) (database.Person, database.Session, error) {
+	token, err := s.Signer.Verify(cookieValue)
+	if err != nil {
+		return database.Person{}, database.Session{}, err
+	}
+	return s.authenticate(ctx, token, database.SessionCookie)
+}
+
+// AuthenticateToken - returns person and session by API token.
+// Returns ErrUnauthenticated if session is not active.
+func (s Sessions) AuthenticateToken(ctx context.Context, token string) (database.Person, database.Session, error) {
+	return s.authenticate(ctx, token, database.SessionToken)
+}
+
+func (s Sessions) start(ctx context.Context, personID uint32, kind database.SessionKind,
+	ttl time.Duration) (string, database.Session, error) {
+	token, hash, err := NewToken()
+	if err != nil {
+		return "", database.Session{}, err
+	}
+
+	session, err := s.Store.Create(ctx, database.Session{
+		TokenHash: hash,
+		PersonID:  personID,
+		Kind:      kind,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", database.Session{}, err
+	}
+	return token, session, nil
+}
+
+func (s Sessions) authenticate(ctx context.Context, token string,
+	kind database.SessionKind) (database.Person, database.Session, error) {
+	session, err := s.Store.GetActiveByTokenHash(ctx, HashToken(token))
+	if errors.Is(err, database.ErrNotFound) {
+		return database.Person{}, database.Session{}, ErrUnauthenticated
+	}
+	if err != nil {
+		return database.Person{}, database.Session{}, fmt.Errorf("Sessions.authenticate() -> %w", err)
+	}
+
+	if session.Kind != kind {
+		return database.Person{}, database.Session{}, ErrUnauthenticated
+	}
+
+	person, err := s.Persons.GetByID(ctx, session.PersonID, database.WithoutRelations())
+	if errors.Is(err, database.ErrNotFound) {
+		return database.Person{}, database.Session{}, ErrUnauthenticated
+	}
+	if err != nil {
+		return database.Person{}, database.Session{}, fmt.Errorf("Sessions.authenticate() -> %w", err)
+	}
+	return person, session, nil
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s4lat/gokan/database"
+)
+
+// fakeSessions - in-memory database.SessionManager.
+type fakeSessions struct {
+	sessions []database.Session
+}
+
+func (fs *fakeSessions) Create(_ context.Context, session database.Session) (database.Session, error) {
+	session.ID = uint32(len(fs.sessions) + 1)
+	session.CreatedAt = time.Now()
+	fs.sessions = append(fs.sessions, session)
+	return session, nil
+}
+
+func (fs *fakeSessions) GetActiveByTokenHash(_ context.Context, tokenHash string) (database.Session, error) {
+	for _, s := range fs.sessions {
+		if s.TokenHash == tokenHash && s.RevokedAt == nil && s.ExpiresAt.After(time.Now()) {
+			return s, nil
+		}
+	}
+	return database.Session{}, database.ErrNotFound
+}
+
+func (fs *fakeSessions) RevokeByID(_ context.Context, sessionID uint32) error {
+	now := time.Now()
+	for i := range fs.sessions {
+		if fs.sessions[i].ID == sessionID {
+			fs.sessions[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (fs *fakeSessions) RevokeAllByPersonID(_ context.Context, personID uint32) error {
+	now := time.Now()
+	for i := range fs.sessions {
+		if fs.sessions[i].PersonID == personID {
+			fs.sessions[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (fs *fakeSessions) DeleteExpired(context.Context) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func newTestSessions(t *testing.T) (Sessions, database.Person) {
+	t.Helper()
+
+	signer, err := NewSigner(strings.Repeat("s", MinSecretLength))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	persons := &fakePersons{}
+	person, err := persons.Create(context.Background(), database.Person{Username: "s4lat", Email: "s4lat@mail.ru"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Sessions{Store: &fakeSessions{}, Persons: persons, Signer: signer}, person
+}
+
+func TestSigner(t *testing.T) {
+	if _, err := NewSigner("short"); err == nil {
+		t.Error("NewSigner() does't return error for short secret")
+	}
+
+	signer, _ := NewSigner(strings.Repeat("a", MinSecretLength))
+	otherSigner, _ := NewSigner(strings.Repeat("b", MinSecretLength))
+
+	signed := signer.Sign("value")
+	value, err := signer.Verify(signed)
+	if err != nil || value != "value" {
+		t.Errorf("Verify() returned (%q, %v) for signed value", value, err)
+	}
+
+	forged := []string{"value", "value.", "other" + signed[len("value"):], otherSigner.Sign("value")}
+	for _, signed := range forged {
+		if _, err := signer.Verify(signed); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("Verify(%q) returned %v for forged value, expected ErrUnauthenticated", signed, err)
+		}
+	}
+}
+
+func TestSessionsCookie(t *testing.T) {
+	ctx := context.Background()
+	sessions, person := newTestSessions(t)
+
+	cookieValue, session, err := sessions.StartCookie(ctx, person.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(cookieValue, session.TokenHash) {
+		t.Error("StartCookie() returned token hash in cookie value")
+	}
+
+	authPerson, authSession, err := sessions.AuthenticateCookie(ctx, cookieValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authPerson.ID != person.ID || authSession.ID != session.ID {
+		t.Errorf("AuthenticateCookie() returned wrong person or session: %v, %v", authPerson, authSession)
+	}
+
+	token, _ := sessions.Signer.Verify(cookieValue)
+	if _, _, err := sessions.AuthenticateToken(ctx, token); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("AuthenticateToken() returned %v for cookie session, expected ErrUnauthenticated", err)
+	}
+
+	if err := sessions.Store.RevokeByID(ctx, session.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sessions.AuthenticateCookie(ctx, cookieValue); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("AuthenticateCookie() returned %v for revoked session, expected ErrUnauthenticated", err)
+	}
+}
+
+func TestSessionsToken(t *testing.T) {
+	ctx := context.Background()
+	sessions, person := newTestSessions(t)
+
+	token, session, err := sessions.IssueToken(ctx, person.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Kind != database.SessionToken || session.TokenHash != HashToken(token) {
+		t.Errorf("IssueToken() created wrong session: %v", session)
+	}
+
+	authPerson, _, err := sessions.AuthenticateToken(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authPerson.ID != person.ID {
+		t.Errorf("AuthenticateToken() returned wrong person: %v", authPerson)
+	}
+
+	if _, _, err := sessions.AuthenticateToken(ctx, token+"x"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("AuthenticateToken() returned %v for unknown token, expected ErrUnauthenticated", err)
+	}
+
+	store := sessions.Store.(*fakeSessions)
+	store.sessions[0].ExpiresAt = time.Now().Add(-time.Minute)
+	if _, _, err := sessions.AuthenticateToken(ctx, token); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("AuthenticateToken() returned %v for expired token, expected ErrUnauthenticated", err)
+	}
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ActivityVerb - kind of change recorded in activity log.
+type ActivityVerb string
+
+// Verbs of activity log.
+const (
+	ActivityCreate ActivityVerb = "create"
+	ActivityUpdate ActivityVerb = "update"
+	ActivityDelete ActivityVerb = "delete"
+	ActivityMove   ActivityVerb = "move"
+)
+
+// Activity - record of board activity log. Activity is written for every change of board, its columns,
+// tags, contributors, tasks and their subtasks, tags, assignees and comments in the same transaction
+// as the change itself, records are never changed or deleted.
+//
+// EntityType is name of changed table and EntityID is ID of changed row, for rows linking task or board
+// with other entity it's ID of that entity, e.g. tag ID for tag of task. Before and After are changed row
+// as JSON object, Before is null for created entity, After is null for deleted one. Entities deleted
+// together with their parent, e.g. tasks of deleted column, aren't logged.
+type Activity struct {
+	CreatedAt  time.Time       `json:"created_at"`
+	TaskID     *uint32         `json:"task_id"`  // set if changed entity is task or belongs to task
+	ActorID    *uint32         `json:"actor_id"` // person set by WithActor, nil if change made without actor
+	Verb       ActivityVerb    `json:"verb"`
+	EntityType string          `json:"entity_type"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	ID         uint32          `json:"activity_id"`
+	BoardID    uint32          `json:"board_id"`
+	EntityID   uint32          `json:"entity_id"`
+}
+
+// activityColumns - columns of activity table, scanned by activityFields.
+const activityColumns = ("activity_id, board_id, task_id, actor_id, activity_verb, entity_type, entity_id, " +
+	"before_data, after_data, created_at")
+
+// activityFields - returns destinations for scanning row selected by activityColumns into a.
+func activityFields(a *Activity) []any {
+	return []any{&a.ID, &a.BoardID, &a.TaskID, &a.ActorID, &a.Verb, &a.EntityType, &a.EntityID,
+		&a.Before, &a.After, &a.CreatedAt}
+}
+
+// ActivityModel - struct that implements ActivityManager interface for interacting with activity table in db.
+type ActivityModel struct {
+	DB DBConn
+}
+
+// GetByBoard - returns page of board activity, newest first.
+func (am ActivityModel) GetByBoard(ctx context.Context, boardID uint32, page Page) ([]Activity, error) {
+	sql := ("SELECT " + activityColumns + " FROM activity " +
+		"WHERE board_id = $1 AND ($2 = 0 OR activity_id < $2) " +
+		"ORDER BY activity_id DESC LIMIT $3;")
+
+	activity, err := queryRows(ctx, am.DB, activityFields, sql, boardID, page.AfterID, page.limit())
+	if err != nil {
+		return nil, fmt.Errorf("ActivityModel.GetByBoard() -> %w", dbError(err))
+	}
+	return activity, nil
+}
+
+// GetByTask - returns page of activity of board task, newest first. Activity of deleted task
+// is returned too, so task is matched together with its board.
+func (am ActivityModel) GetByTask(ctx context.Context, boardID, taskID uint32, page Page) ([]Activity, error) {
+	sql := ("SELECT " + activityColumns + " FROM activity " +
+		"WHERE board_id = $1 AND task_id = $2 AND ($3 = 0 OR activity_id < $3) " +
+		"ORDER BY activity_id DESC LIMIT $4;")
+
+	activity, err := queryRows(ctx, am.DB, activityFields, sql, boardID, taskID, page.AfterID, page.limit())
+	if err != nil {
+		return nil, fmt.Errorf("ActivityModel.GetByTask() -> %w", dbError(err))
+	}
+	return activity, nil
+}
+
+// setActivityActor - makes person set by WithActor in ctx the actor of activity written in transaction tx.
+func setActivityActor(ctx context.Context, tx pgx.Tx) error {
+	actor := actorID(ctx)
+	if actor == nil {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, "SELECT set_config('gokan.actor_id', $1, true);", fmt.Sprint(*actor))
+	return err
+}
+
+// logMove - writes move of task or subtask of task with taskID to activity log, must be called in
+// transaction. Triggers don't log changes of positions, because they are also changed by renumbering.
+func logMove(ctx context.Context, tx pgx.Tx, entityType string, entityID, taskID uint32,
+	before, after map[string]any) error {
+	sql := ("INSERT INTO activity " +
+		"(board_id, task_id, actor_id, activity_verb, entity_type, entity_id, before_data, after_data) " +
+		"SELECT board_id, task_id, activity_actor(), 'move', $1::VARCHAR, $2::INTEGER, $4::JSONB, $5::JSONB " +
+		"FROM task WHERE task_id = $3;")
+
+	_, err := tx.Exec(ctx, sql, entityType, entityID, taskID, before, after)
+	return err
+}
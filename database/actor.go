@@ -0,0 +1,21 @@
+package database
+
+import "context"
+
+// actorCtxKey - key of person, who makes changes, in context.
+type actorCtxKey struct{}
+
+// WithActor - returns copy of ctx carrying ID of person, who makes changes through managers
+// called with this context, e.g. it's saved as last_modified_by of changed tasks.
+func WithActor(ctx context.Context, personID uint32) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, personID)
+}
+
+// actorID - returns ID of person set by WithActor, nil if ctx has no actor.
+func actorID(ctx context.Context) *uint32 {
+	personID, ok := ctx.Value(actorCtxKey{}).(uint32)
+	if !ok {
+		return nil
+	}
+	return &personID
+}
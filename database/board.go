@@ -3,24 +3,50 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // Board - board model struct.
 type Board struct {
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	DeletedAt    *time.Time    `json:"deleted_at"`  // set if board is in trash
+	ArchivedAt   *time.Time    `json:"archived_at"` // set if board is archived
 	Owner        BoardOwner    `json:"owner"`
 	Name         string        `json:"board_name"`
-	Contributors []Contributor // LoadBoardContributors() by person_id from contributor table
-	Tasks        []Task
-	Tags         []Tag
-	ID           uint32 `json:"board_id"`
+	Contributors []Contributor `json:"contributors"` // LoadBoardContributors() by person_id from contributor table
+	Columns      []Column      `json:"columns"`      // ordered by Column.Position, each column holds its ordered tasks
+	Tasks        []Task        `json:"tasks"`
+	Tags         []Tag         `json:"tags"`
+	ID           uint32        `json:"board_id"`
 }
 
 // SmallBoard - is a struct, that used to save board data in some other structs, when
 // we don't need to save all board information like contributors, tasks, tags.
 type SmallBoard struct {
-	Name  string
-	Owner BoardOwner
-	ID    uint32
+	Name  string     `json:"board_name"`
+	Owner BoardOwner `json:"owner"`
+	ID    uint32     `json:"board_id"`
+}
+
+// boardColumns - columns of board table joined with owner from person table, scanned by boardFields.
+const boardColumns = smallBoardColumns + ", board.created_at, board.updated_at, board.deleted_at, " +
+	"board.archived_at"
+
+// boardFields - returns destinations for scanning row selected by boardColumns into b.
+func boardFields(b *Board) []any {
+	fields := append([]any{&b.ID, &b.Name}, smallPersonFields((*SmallPerson)(&b.Owner))...)
+	return append(fields, &b.CreatedAt, &b.UpdatedAt, &b.DeletedAt, &b.ArchivedAt)
+}
+
+// smallBoardColumns - columns of board table joined with owner from person table, scanned by smallBoardFields.
+const smallBoardColumns = "board.board_id, board.board_name, " + smallPersonColumns
+
+// smallBoardFields - returns destinations for scanning row selected by smallBoardColumns into b.
+func smallBoardFields(b *SmallBoard) []any {
+	return append([]any{&b.ID, &b.Name}, smallPersonFields((*SmallPerson)(&b.Owner))...)
 }
 
 // Small - return SmallBoard representation of Person.
@@ -33,11 +59,37 @@ type BoardOwner SmallPerson
 
 // Contributor - struct that used to represent contributors(persons) in Board.Contributors field.
 type Contributor struct {
-	Username  string
-	FirstName string
-	LastName  string
-	Email     string
-	ID        uint32
+	Username  string          `json:"username"`
+	FirstName string          `json:"first_name"`
+	LastName  string          `json:"last_name"`
+	Email     string          `json:"-"` // private, see SmallPerson.Email
+	Role      ContributorRole `json:"role"`
+	ID        uint32          `json:"person_id"`
+}
+
+// contributorColumns - columns of contributor table joined with person table, scanned by contributorFields.
+const contributorColumns = smallPersonColumns + ", contributor.contributor_role"
+
+// contributorFields - returns destinations for scanning row selected by contributorColumns into c.
+func contributorFields(c *Contributor) []any {
+	return []any{&c.ID, &c.Username, &c.FirstName, &c.LastName, &c.Email, &c.Role}
+}
+
+// ContributorRole - role of contributor in board, defines what contributor can do with board.
+type ContributorRole string
+
+const (
+	// ContributorAdmin - can manage board and its contributors, but can't delete board.
+	ContributorAdmin ContributorRole = "admin"
+	// ContributorMember - can edit tasks, tags and columns of board, default role.
+	ContributorMember ContributorRole = "member"
+	// ContributorViewer - can only read board.
+	ContributorViewer ContributorRole = "viewer"
+)
+
+// BoardUpdate - fields to change in BoardModel.Update, nil fields are left unchanged.
+type BoardUpdate struct {
+	Name *string `json:"board_name"`
 }
 
 // BoardModel - struct that implements BoardManager interface for interacting with board table in db.
@@ -45,108 +97,325 @@ type BoardModel struct {
 	DB DBConn
 }
 
-// Create - Creates new row in table 'board'.
+// Create - Creates new row in table 'board' and DefaultColumns for it.
 // Returning created Board.
 func (bm BoardModel) Create(ctx context.Context, board Board) (Board, error) {
 	sql := ("WITH inserted_board AS ( " +
 		"INSERT INTO board (board_name, owner_id) " +
-		"VALUES ($1, $2) RETURNING *) " +
-		"SELECT inserted_board.*, username, first_name, last_name, email " +
-		"FROM inserted_board JOIN person ON person_id = owner_id;")
+		"VALUES ($1, $2) " +
+		"RETURNING board_id, board_name, owner_id, created_at, updated_at, deleted_at, archived_at), " +
+		"inserted_columns AS ( " +
+		"INSERT INTO \"column\" (column_name, board_id, column_position) " +
+		"SELECT default_column.column_name, inserted_board.board_id, default_column.column_position " +
+		"FROM inserted_board, " +
+		"unnest($3::VARCHAR[]) WITH ORDINALITY AS default_column(column_name, column_position)) " +
+		"SELECT " + boardColumns + " " +
+		"FROM inserted_board AS board JOIN person ON person.person_id = board.owner_id;")
 
 	var createdBoard Board
-	err := bm.DB.QueryRow(ctx, sql,
-		board.Name,
-		board.Owner.ID,
-	).Scan(
-		&createdBoard.ID,
-		&createdBoard.Name,
-		&createdBoard.Owner.ID,
-		&createdBoard.Owner.Username,
-		&createdBoard.Owner.FirstName,
-		&createdBoard.Owner.LastName,
-		&createdBoard.Owner.Email,
-	)
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		var err error
+		createdBoard, err = queryRow(ctx, tx, boardFields, sql,
+			board.Name,
+			board.Owner.ID,
+			DefaultColumns,
+		)
+		return err
+	})
 
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.Create() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.Create() -> %w", dbError(err))
 	}
 
 	return createdBoard, nil
 }
 
-// DeleteByID - deletes row from table 'board'.
+// DeleteByID - moves board to trash, board in trash is hidden from getters with all its relations
+// and can be brought back by Restore, until it is purged by PurgeDeleted.
 func (bm BoardModel) DeleteByID(ctx context.Context, boardID uint32) error {
-	sql := "DELETE FROM board WHERE board_id = $1;"
-	_, err := bm.DB.Exec(ctx, sql, boardID)
+	sql := "UPDATE board SET deleted_at = now() WHERE board_id = $1 AND deleted_at IS NULL;"
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, sql, boardID)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("BoardModel.DeleteByID() -> %w", err)
+		return fmt.Errorf("BoardModel.DeleteByID() -> %w", dbError(err))
 	}
 	return nil
 }
 
-// GetByID - searching for board in DB by ID, returning finded Board.
-func (bm BoardModel) GetByID(ctx context.Context, boardID uint32) (Board, error) {
-	sql := ("SELECT board.*, username, first_name, last_name, email " +
-		"FROM board JOIN person ON person_id = owner_id " +
-		"WHERE board_id = $1")
-
-	var obtainedBoard Board
-	err := bm.DB.QueryRow(ctx, sql, boardID).Scan(
-		&obtainedBoard.ID,
-		&obtainedBoard.Name,
-		&obtainedBoard.Owner.ID,
-		&obtainedBoard.Owner.Username,
-		&obtainedBoard.Owner.FirstName,
-		&obtainedBoard.Owner.LastName,
-		&obtainedBoard.Owner.Email,
-	)
+// Restore - brings board owned by person with ownerID back from trash with all its relations.
+// Returns ErrNotFound if board isn't in trash or is owned by other person. Returning restored Board.
+func (bm BoardModel) Restore(ctx context.Context, boardID, ownerID uint32) (Board, error) {
+	sql := ("UPDATE board SET deleted_at = NULL " +
+		"WHERE board_id = $1 AND owner_id = $2 AND deleted_at IS NOT NULL RETURNING board_id;")
+
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, boardID, ownerID).Scan(&boardID)
+	})
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.Restore() -> %w", dbError(err))
+	}
 
+	restoredBoard, err := bm.GetByID(ctx, boardID)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.GetByID() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.Restore() -> %w", dbError(err))
 	}
+	return restoredBoard, nil
+}
+
+// GetDeleted - returns boards owned by person, that are in trash, recently deleted first.
+// Boards are returned without related data.
+func (bm BoardModel) GetDeleted(ctx context.Context, ownerID uint32) ([]Board, error) {
+	sql := ("SELECT " + boardColumns + " " +
+		"FROM board JOIN person ON person.person_id = board.owner_id " +
+		"WHERE board.owner_id = $1 AND board.deleted_at IS NOT NULL " +
+		"ORDER BY board.deleted_at DESC, board.board_id")
+
+	boards, err := queryRows(ctx, bm.DB, boardFields, sql, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("BoardModel.GetDeleted() -> %w", dbError(err))
+	}
+	return boards, nil
+}
+
+// PurgeDeleted - permanently deletes boards moved to trash before deletedBefore together with
+// all their data, returns count of deleted boards.
+func (bm BoardModel) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	sql := "DELETE FROM board WHERE deleted_at < $1;"
+
+	var purged int64
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, sql, deletedBefore)
+		purged = cmdTag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("BoardModel.PurgeDeleted() -> %w", dbError(err))
+	}
+	return purged, nil
+}
 
-	obtainedBoard, err = bm.loadEverything(ctx, obtainedBoard)
+// Archive - archives board, archived board keeps all its data and stays available by GetByID,
+// but it is listed in Person.ArchivedBoards and its tasks are hidden from person views until
+// it is brought back by Unarchive. Archiving archived board does nothing.
+// Returning archived Board.
+func (bm BoardModel) Archive(ctx context.Context, boardID uint32) (Board, error) {
+	sql := ("UPDATE board SET archived_at = COALESCE(archived_at, now()) " +
+		"WHERE board_id = $1 AND deleted_at IS NULL RETURNING board_id;")
+
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, boardID).Scan(&boardID)
+	})
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.GetByID() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.Archive() -> %w", dbError(err))
+	}
+
+	archivedBoard, err := bm.GetByID(ctx, boardID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.Archive() -> %w", dbError(err))
+	}
+	return archivedBoard, nil
+}
+
+// Unarchive - brings archived board back to active boards, unarchiving active board does nothing.
+// Returning unarchived Board.
+func (bm BoardModel) Unarchive(ctx context.Context, boardID uint32) (Board, error) {
+	sql := "UPDATE board SET archived_at = NULL WHERE board_id = $1 AND deleted_at IS NULL RETURNING board_id;"
+
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, boardID).Scan(&boardID)
+	})
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.Unarchive() -> %w", dbError(err))
+	}
+
+	unarchivedBoard, err := bm.GetByID(ctx, boardID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.Unarchive() -> %w", dbError(err))
+	}
+	return unarchivedBoard, nil
+}
+
+// Update - updates row in table 'board' with non-nil fields of `update`.
+// Returning updated Board.
+func (bm BoardModel) Update(ctx context.Context, boardID uint32, update BoardUpdate) (Board, error) {
+	sql := ("UPDATE board SET " +
+		"board_name = COALESCE($2, board_name) " +
+		"WHERE board_id = $1 AND deleted_at IS NULL RETURNING board_id;")
+
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, boardID, update.Name).Scan(&boardID)
+	})
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.Update() -> %w", dbError(err))
+	}
+
+	updatedBoard, err := bm.GetByID(ctx, boardID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.Update() -> %w", dbError(err))
+	}
+	return updatedBoard, nil
+}
+
+// GetByID - searching for board in DB by ID, returning finded Board. Archived tasks of board
+// are loaded only if WithArchived is passed, WithQuery filters loaded tasks.
+func (bm BoardModel) GetByID(ctx context.Context, boardID uint32, opts ...LoadOption) (Board, error) {
+	sql := ("SELECT " + boardColumns + " " +
+		"FROM board JOIN person ON person.person_id = board.owner_id " +
+		"WHERE board.board_id = $1 AND board.deleted_at IS NULL")
+
+	obtainedBoard, err := queryRow(ctx, bm.DB, boardFields, sql, boardID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.GetByID() -> %w", dbError(err))
+	}
+
+	obtainedBoard, err = bm.loadEverything(ctx, obtainedBoard, newLoadOptions(opts))
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.GetByID() -> %w", dbError(err))
 	}
 
 	return obtainedBoard, nil
 }
 
-// AddContributorToBoard - adds row in contributor table with values (person.ID, board.ID).
+// GetSmallByID - searching for board in DB by ID without any related data, returning finded SmallBoard.
+func (bm BoardModel) GetSmallByID(ctx context.Context, boardID uint32) (SmallBoard, error) {
+	board, err := bm.GetByID(ctx, boardID, WithoutRelations())
+	if err != nil {
+		return SmallBoard{}, fmt.Errorf("BoardModel.GetSmallByID() -> %w", dbError(err))
+	}
+	return board.Small(), nil
+}
+
+// AddContributorToBoard - adds row in contributor table with values (person.ID, board.ID, contrib.Role),
+// contributor with empty role is added as ContributorMember. Returns ErrInvalidInput if contrib is board owner.
 func (bm BoardModel) AddContributorToBoard(ctx context.Context, contrib Contributor, board Board) (Board, error) {
 	if contrib.ID == board.Owner.ID {
-		return Board{}, fmt.Errorf("BoardModel.AddPersonToBoard ->" +
-			"person is board owner, no need to add in contributors")
+		return Board{}, fmt.Errorf("BoardModel.AddContributorToBoard() -> %w",
+			newError(ErrInvalidInput, "person is board owner, no need to add in contributors"))
+	}
+
+	if contrib.Role == "" {
+		contrib.Role = ContributorMember
 	}
 
-	sql := "INSERT INTO contributor (person_id, board_id) VALUES ($1, $2);"
-	_, err := bm.DB.Exec(ctx, sql, contrib.ID, board.ID)
+	sql := "INSERT INTO contributor (person_id, board_id, contributor_role) VALUES ($1, $2, $3);"
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, sql, contrib.ID, board.ID, contrib.Role)
+		return err
+	})
 
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.AddPersonToBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.AddContributorToBoard() -> %w", dbError(err))
 	}
 
 	board, err = bm.loadContributors(ctx, board)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.AddPersonToBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.AddContributorToBoard() -> %w", dbError(err))
+	}
+
+	return board, nil
+}
+
+// SetContributorRole - changes role of board contributor to contrib.Role.
+// Returns ErrNotFound if person is not contributor of board.
+func (bm BoardModel) SetContributorRole(ctx context.Context, contrib Contributor, board Board) (Board, error) {
+	sql := "UPDATE contributor SET contributor_role = $1 WHERE person_id = $2 AND board_id = $3;"
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, sql, contrib.Role, contrib.ID, board.ID)
+		if err != nil {
+			return err
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return newError(ErrNotFound, "person %d is not contributor of board %d", contrib.ID, board.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.SetContributorRole() -> %w", dbError(err))
+	}
+
+	board, err = bm.loadContributors(ctx, board)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.SetContributorRole() -> %w", dbError(err))
+	}
+	return board, nil
+}
+
+// TransferOwnership - makes contributor newOwner the owner of board, previous owner becomes
+// contributor with ContributorAdmin role. Returns ErrNotFound if newOwner is not contributor of board,
+// ErrConflict if board is in trash.
+func (bm BoardModel) TransferOwnership(ctx context.Context, board Board, newOwner Contributor) (Board, error) {
+	const addOldOwnerSQL = ("INSERT INTO contributor (person_id, board_id, contributor_role) " +
+		"VALUES ($1, $2, $3);")
+
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		oldOwnerID, err := reassignBoardOwner(ctx, tx, board.ID, newOwner.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, addOldOwnerSQL, oldOwnerID, board.ID, ContributorAdmin)
+		return err
+	})
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.TransferOwnership() -> %w", dbError(err))
 	}
 
+	board, err = bm.GetByID(ctx, board.ID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.TransferOwnership() -> %w", dbError(err))
+	}
 	return board, nil
 }
 
+// reassignBoardOwner - sets owner of board with boardID to contributor with newOwnerID and removes them
+// from contributors, must be called in transaction. Returns ID of previous owner,
+// ErrConflict if board is in trash.
+func reassignBoardOwner(ctx context.Context, tx pgx.Tx, boardID, newOwnerID uint32) (uint32, error) {
+	const (
+		lockBoardSQL         = "SELECT owner_id, deleted_at IS NOT NULL FROM board WHERE board_id = $1 FOR UPDATE;"
+		removeContributorSQL = "DELETE FROM contributor WHERE person_id = $1 AND board_id = $2;"
+		setOwnerSQL          = "UPDATE board SET owner_id = $1 WHERE board_id = $2;"
+	)
+
+	var oldOwnerID uint32
+	var isDeleted bool
+	if err := tx.QueryRow(ctx, lockBoardSQL, boardID).Scan(&oldOwnerID, &isDeleted); err != nil {
+		return 0, err
+	}
+	if isDeleted {
+		return 0, newError(ErrConflict, "board %d is in trash", boardID)
+	}
+
+	cmdTag, err := tx.Exec(ctx, removeContributorSQL, newOwnerID, boardID)
+	if err != nil {
+		return 0, err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return 0, newError(ErrNotFound, "person %d is not contributor of board %d", newOwnerID, boardID)
+	}
+
+	if _, err := tx.Exec(ctx, setOwnerSQL, newOwnerID, boardID); err != nil {
+		return 0, err
+	}
+	return oldOwnerID, nil
+}
+
 // RemoveContributorFromBoard - removes row in contributor table with values (person.ID, board.ID).
 func (bm BoardModel) RemoveContributorFromBoard(ctx context.Context, contrib Contributor, board Board) (Board, error) {
 	sql := "DELETE FROM contributor WHERE person_id = $1 AND board_id = $2"
-	_, err := bm.DB.Exec(ctx, sql, contrib.ID, board.ID)
+	err := inTx(ctx, bm.DB, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, sql, contrib.ID, board.ID)
+		return err
+	})
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.RemoveContributorFromBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.RemoveContributorFromBoard() -> %w", dbError(err))
 	}
 
 	board, err = bm.GetByID(ctx, board.ID)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.RemoveContributorFromBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.RemoveContributorFromBoard() -> %w", dbError(err))
 	}
 	return board, nil
 }
@@ -156,12 +425,17 @@ func (bm BoardModel) AddTaskToBoard(ctx context.Context, task Task, board Board)
 	task.BoardID = board.ID
 	_, err := TaskModel(bm).Create(ctx, task)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.AddTaskToBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.AddTaskToBoard() -> %w", dbError(err))
 	}
 
-	board, err = bm.loadTasks(ctx, board)
+	board, err = bm.loadTasks(ctx, board, newLoadOptions(nil))
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.GetByID() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.AddTaskToBoard() -> %w", dbError(err))
+	}
+
+	board, err = bm.loadColumns(ctx, board)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.AddTaskToBoard() -> %w", dbError(err))
 	}
 
 	return board, nil
@@ -170,18 +444,54 @@ func (bm BoardModel) AddTaskToBoard(ctx context.Context, task Task, board Board)
 // RemoveTaskFromBoard - removes task from board.
 func (bm BoardModel) RemoveTaskFromBoard(ctx context.Context, task Task, board Board) (Board, error) {
 	if task.BoardID != board.ID {
-		return Board{}, fmt.Errorf("BoardModel.RemoveTaskFromBoard() -> task.BoardID(%d) != board.ID(%d)",
-			task.BoardID, board.ID)
+		return Board{}, fmt.Errorf("BoardModel.RemoveTaskFromBoard() -> %w",
+			newError(ErrInvalidInput, "task.BoardID(%d) != board.ID(%d)", task.BoardID, board.ID))
 	}
 
 	err := TaskModel(bm).DeleteByID(ctx, task.ID)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.RemoveTaskFromBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.RemoveTaskFromBoard() -> %w", dbError(err))
+	}
+
+	board, err = bm.GetByID(ctx, board.ID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.RemoveTaskFromBoard() -> %w", dbError(err))
+	}
+	return board, nil
+}
+
+// AddColumnToBoard - add column to table 'column' in db with board_id = board.ID,
+// new column is placed after all other board columns.
+func (bm BoardModel) AddColumnToBoard(ctx context.Context, column Column, board Board) (Board, error) {
+	column.BoardID = board.ID
+	_, err := ColumnModel(bm).Create(ctx, column)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.AddColumnToBoard() -> %w", dbError(err))
 	}
 
 	board, err = bm.GetByID(ctx, board.ID)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.RemoveTaskFromBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.AddColumnToBoard() -> %w", dbError(err))
+	}
+
+	return board, nil
+}
+
+// RemoveColumnFromBoard - removes column without tasks from board.
+func (bm BoardModel) RemoveColumnFromBoard(ctx context.Context, column Column, board Board) (Board, error) {
+	if column.BoardID != board.ID {
+		return Board{}, fmt.Errorf("BoardModel.RemoveColumnFromBoard() -> %w",
+			newError(ErrInvalidInput, "column.BoardID(%d) != board.ID(%d)", column.BoardID, board.ID))
+	}
+
+	err := ColumnModel(bm).DeleteByID(ctx, column.ID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.RemoveColumnFromBoard() -> %w", dbError(err))
+	}
+
+	board, err = bm.GetByID(ctx, board.ID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.RemoveColumnFromBoard() -> %w", dbError(err))
 	}
 	return board, nil
 }
@@ -191,12 +501,12 @@ func (bm BoardModel) AddTagToBoard(ctx context.Context, tag Tag, board Board) (B
 	tag.BoardID = board.ID
 	_, err := TagModel(bm).Create(ctx, tag)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.AddTagToBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.AddTagToBoard() -> %w", dbError(err))
 	}
 
 	board, err = bm.loadTags(ctx, board)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.AddTagToBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.AddTagToBoard() -> %w", dbError(err))
 	}
 
 	return board, nil
@@ -205,66 +515,63 @@ func (bm BoardModel) AddTagToBoard(ctx context.Context, tag Tag, board Board) (B
 // RemoveTagFromBoard - removes task from board.
 func (bm BoardModel) RemoveTagFromBoard(ctx context.Context, tag Tag, board Board) (Board, error) {
 	if tag.BoardID != board.ID {
-		return Board{}, fmt.Errorf("BoardModel.RemoveTagFromBoard() -> tag.BoardID(%d) != board.ID(%d)",
-			tag.BoardID, board.ID)
+		return Board{}, fmt.Errorf("BoardModel.RemoveTagFromBoard() -> %w",
+			newError(ErrInvalidInput, "tag.BoardID(%d) != board.ID(%d)", tag.BoardID, board.ID))
 	}
 
 	err := TagModel(bm).DeleteByID(ctx, tag.ID)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.RemoveTagFromBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.RemoveTagFromBoard() -> %w", dbError(err))
 	}
 
 	board, err = bm.GetByID(ctx, board.ID)
 	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.RemoveTagFromBoard() -> %w", err)
+		return Board{}, fmt.Errorf("BoardModel.RemoveTagFromBoard() -> %w", dbError(err))
 	}
 	return board, nil
 }
 
 // loadContributors - loading contributors in Board.Contributors slice.
 func (bm BoardModel) loadContributors(ctx context.Context, board Board) (Board, error) {
-	sql := ("SELECT contributor.person_id, " +
-		"person.username, person.first_name, person.last_name, person.email " +
+	sql := ("SELECT " + contributorColumns + " " +
 		"FROM contributor JOIN person ON person.person_id = contributor.person_id " +
-		"WHERE board_id = $1")
-
-	rows, _ := bm.DB.Query(ctx, sql, board.ID)
-	defer rows.Close()
-
-	var contributors []Contributor
-	for rows.Next() {
-		var contributor Contributor
-		err := rows.Scan(&contributor.ID, &contributor.Username,
-			&contributor.FirstName, &contributor.LastName, &contributor.Email)
-		if err != nil {
-			return Board{}, fmt.Errorf("BoardModel.loadContributors() -> %w", err)
-		}
-		contributors = append(contributors, contributor)
-	}
+		"WHERE contributor.board_id = $1")
 
-	if err := rows.Err(); err != nil {
-		return Board{}, fmt.Errorf("BoardModel.loadContributors() -> %w", err)
+	contributors, err := queryRows(ctx, bm.DB, contributorFields, sql, board.ID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.loadContributors() -> %w", dbError(err))
 	}
 
 	board.Contributors = contributors
 	return board, nil
 }
 
-// loadEverything - combines loadTags, loadTasks, loadContributors in one method.
-func (bm BoardModel) loadEverything(ctx context.Context, board Board) (Board, error) {
-	board, err := bm.loadTags(ctx, board)
-	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.loadEverything() -> %w", err)
+// loadEverything - combines loadTags, loadTasks, loadColumns, loadContributors in one method,
+// loading only data selected by o.
+func (bm BoardModel) loadEverything(ctx context.Context, board Board, o loadOptions) (Board, error) {
+	var err error
+	if o.tags {
+		if board, err = bm.loadTags(ctx, board); err != nil {
+			return Board{}, fmt.Errorf("BoardModel.loadEverything() -> %w", dbError(err))
+		}
 	}
 
-	board, err = bm.loadTasks(ctx, board)
-	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.loadEverything() -> %w", err)
+	if o.tasks {
+		if board, err = bm.loadTasks(ctx, board, o); err != nil {
+			return Board{}, fmt.Errorf("BoardModel.loadEverything() -> %w", dbError(err))
+		}
 	}
 
-	board, err = bm.loadContributors(ctx, board)
-	if err != nil {
-		return Board{}, fmt.Errorf("BoardModel.loadEverything() -> %w", err)
+	if o.columns {
+		if board, err = bm.loadColumns(ctx, board); err != nil {
+			return Board{}, fmt.Errorf("BoardModel.loadEverything() -> %w", dbError(err))
+		}
+	}
+
+	if o.contributors {
+		if board, err = bm.loadContributors(ctx, board); err != nil {
+			return Board{}, fmt.Errorf("BoardModel.loadEverything() -> %w", dbError(err))
+		}
 	}
 
 	return board, nil
@@ -272,55 +579,56 @@ func (bm BoardModel) loadEverything(ctx context.Context, board Board) (Board, er
 
 // loadTags - loading tags in Board.Tags slice.
 func (bm BoardModel) loadTags(ctx context.Context, board Board) (Board, error) {
-	sql := "SELECT * FROM tag WHERE board_id=$1"
-
-	rows, _ := bm.DB.Query(ctx, sql, board.ID)
-	defer rows.Close()
-
-	var tags []Tag
-	for rows.Next() {
-		var tag Tag
-		err := rows.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.BoardID)
-		if err != nil {
-			return Board{}, fmt.Errorf("BoardModel.loadTags() -> %w", err)
-		}
-		tags = append(tags, tag)
-	}
+	sql := "SELECT " + tagColumns + " FROM tag WHERE tag.board_id = $1"
 
-	if err := rows.Err(); err != nil {
-		return Board{}, fmt.Errorf("BoardModel.loadTags() -> %w", err)
+	tags, err := queryRows(ctx, bm.DB, tagFields, sql, board.ID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.loadTags() -> %w", dbError(err))
 	}
 
 	board.Tags = tags
 	return board, nil
 }
 
-// loadTasks - loading tasks in Board.Tasks slice.
-func (bm BoardModel) loadTasks(ctx context.Context, board Board) (Board, error) {
-	sql := "SELECT task_id FROM task WHERE board_id=$1"
+// loadColumns - loading columns in Board.Columns slice and grouping
+// already loaded Board.Tasks by them.
+func (bm BoardModel) loadColumns(ctx context.Context, board Board) (Board, error) {
+	sql := ("SELECT " + columnColumns + " " +
+		"FROM \"column\" WHERE \"column\".board_id = $1 ORDER BY \"column\".column_position")
 
-	rows, _ := bm.DB.Query(ctx, sql, board.ID)
-	defer rows.Close()
+	columns, err := queryRows(ctx, bm.DB, columnFields, sql, board.ID)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.loadColumns() -> %w", dbError(err))
+	}
 
-	localTaskModel := TaskModel(bm)
-	var tasks []Task
-	for rows.Next() {
-		var taskID uint32
-		err := rows.Scan(&taskID)
-		if err != nil {
-			return Board{}, fmt.Errorf("BoardModel.loadTasks() -> %w", err)
-		}
+	columnIndex := make(map[uint32]int, len(columns))
+	for i, column := range columns {
+		columnIndex[column.ID] = i
+	}
 
-		task, err := localTaskModel.GetByID(ctx, taskID)
-		if err != nil {
-			return Board{}, fmt.Errorf("BoardModel.loadTasks() -> %w", err)
+	// board.Tasks are already ordered by task position
+	for _, task := range board.Tasks {
+		if i, ok := columnIndex[task.ColumnID]; ok {
+			columns[i].Tasks = append(columns[i].Tasks, task)
 		}
-
-		tasks = append(tasks, task)
 	}
 
-	if err := rows.Err(); err != nil {
-		return Board{}, fmt.Errorf("BoardModel.loadTasks() -> %w", err)
+	board.Columns = columns
+	return board, nil
+}
+
+// loadTasks - loading tasks in Board.Tasks slice ordered by column and position in it, only tasks
+// matching o.query are loaded. Archived tasks are loaded only if o.archived is set and follow active
+// tasks of their column.
+func (bm BoardModel) loadTasks(ctx context.Context, board Board, o loadOptions) (Board, error) {
+	args := queryArgs{board.ID}
+	clauses := ("JOIN \"column\" ON \"column\".column_id = task.column_id " +
+		"WHERE task.board_id = $1 AND task.deleted_at IS NULL AND " + o.tasksCondition(&args) + " " +
+		"ORDER BY column_position, task.archived_at IS NOT NULL, task_position")
+
+	tasks, err := TaskModel(bm).getMany(ctx, o, clauses, args...)
+	if err != nil {
+		return Board{}, fmt.Errorf("BoardModel.loadTasks() -> %w", dbError(err))
 	}
 
 	board.Tasks = tasks
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultColumns - names of columns that created for every new board.
+var DefaultColumns = []string{"To Do", "In Progress", "Done"}
+
+// Column - column model struct, column (list) is a lane of the board
+// that holds ordered tasks, like "To Do" or "Done".
+type Column struct {
+	Name          string  `json:"column_name"`
+	Tasks         []Task  `json:"tasks"`          // ordered by Task.Position
+	EstimateTotal float64 `json:"estimate_total"` // sum of estimates of active column tasks
+	ID            uint32  `json:"column_id"`
+	BoardID       uint32  `json:"board_id"`
+	Position      uint32  `json:"column_position"`
+}
+
+// columnColumns - columns of column table with total estimate of active column tasks, scanned by columnFields.
+const columnColumns = ("\"column\".column_id, \"column\".column_name, \"column\".board_id, " +
+	"\"column\".column_position, " +
+	"(SELECT COALESCE(SUM(task.estimate), 0) FROM task " +
+	"WHERE task.column_id = \"column\".column_id AND task.deleted_at IS NULL AND task.archived_at IS NULL)")
+
+// columnFields - returns destinations for scanning row selected by columnColumns into c.
+func columnFields(c *Column) []any {
+	return []any{&c.ID, &c.Name, &c.BoardID, &c.Position, &c.EstimateTotal}
+}
+
+// ColumnUpdate - fields to change in ColumnModel.Update, nil fields are left unchanged.
+type ColumnUpdate struct {
+	Name *string `json:"column_name"`
+}
+
+// ColumnModel - struct that implements ColumnManager interface for interacting with column table in db.
+type ColumnModel struct {
+	DB DBConn
+}
+
+// Create - Creates new row in table 'column', column is placed after all other columns of the board.
+// Returning created Column.
+//
+// Don't use directly, to create new column use BoardModel.AddColumnToBoard.
+func (cm ColumnModel) Create(ctx context.Context, column Column) (Column, error) {
+	const (
+		// locking board row serializes columns creation, so concurrent columns don't get the same position
+		lockBoardSQL = "SELECT board_id FROM board WHERE board_id = $1 FOR NO KEY UPDATE;"
+
+		createSQL = ("INSERT INTO \"column\" (column_name, board_id, column_position) " +
+			"SELECT $1::VARCHAR, $2::INTEGER, COALESCE(MAX(column_position), 0) + 1 " +
+			"FROM \"column\" WHERE board_id = $2 " +
+			"RETURNING " + columnColumns + ";")
+	)
+
+	var createdColumn Column
+	err := inTx(ctx, cm.DB, func(tx pgx.Tx) error {
+		var boardID uint32
+		if err := tx.QueryRow(ctx, lockBoardSQL, column.BoardID).Scan(&boardID); err != nil {
+			return err
+		}
+
+		var err error
+		createdColumn, err = queryRow(ctx, tx, columnFields, createSQL,
+			column.Name,
+			column.BoardID,
+		)
+		return err
+	})
+
+	if err != nil {
+		return Column{}, fmt.Errorf("ColumnModel.Create() -> %w", dbError(err))
+	}
+
+	return createdColumn, nil
+}
+
+// DeleteByID - deletes row from table 'column', returns ErrConflict if column has tasks,
+// including archived tasks and tasks in trash, as they must stay restorable until purged.
+func (cm ColumnModel) DeleteByID(ctx context.Context, columnID uint32) error {
+	const (
+		// locked column can't get new tasks until deletion is committed
+		lockColumnSQL = "SELECT column_id FROM \"column\" WHERE column_id = $1 FOR UPDATE;"
+
+		hasTasksSQL = "SELECT EXISTS (SELECT 1 FROM task WHERE column_id = $1);"
+
+		deleteColumnSQL = "DELETE FROM \"column\" WHERE column_id = $1;"
+	)
+
+	err := inTx(ctx, cm.DB, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, lockColumnSQL, columnID).Scan(&columnID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var hasTasks bool
+		if err := tx.QueryRow(ctx, hasTasksSQL, columnID).Scan(&hasTasks); err != nil {
+			return err
+		}
+		if hasTasks {
+			return newError(ErrConflict, "column %d has tasks", columnID)
+		}
+
+		_, err = tx.Exec(ctx, deleteColumnSQL, columnID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("ColumnModel.DeleteByID() -> %w", dbError(err))
+	}
+	return nil
+}
+
+// Update - updates row in table 'column' with non-nil fields of `update`.
+// Returning updated Column.
+func (cm ColumnModel) Update(ctx context.Context, columnID uint32, update ColumnUpdate) (Column, error) {
+	sql := ("UPDATE \"column\" SET " +
+		"column_name = COALESCE($2, column_name) " +
+		"WHERE column_id = $1 RETURNING column_id;")
+
+	err := inTx(ctx, cm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, columnID, update.Name).Scan(&columnID)
+	})
+	if err != nil {
+		return Column{}, fmt.Errorf("ColumnModel.Update() -> %w", dbError(err))
+	}
+
+	updatedColumn, err := cm.GetByID(ctx, columnID)
+	if err != nil {
+		return Column{}, fmt.Errorf("ColumnModel.Update() -> %w", dbError(err))
+	}
+	return updatedColumn, nil
+}
+
+// ArchiveTasks - archives all active tasks of column, see TaskModel.Archive.
+// Person set by WithActor in ctx is saved as last modifier of tasks. Returns count of archived tasks.
+func (cm ColumnModel) ArchiveTasks(ctx context.Context, columnID uint32) (int64, error) {
+	sql := ("UPDATE task SET archived_at = now(), last_modified_by = COALESCE($2, last_modified_by) " +
+		"WHERE column_id = $1 AND deleted_at IS NULL AND archived_at IS NULL;")
+
+	var archived int64
+	err := inTx(ctx, cm.DB, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, sql, columnID, actorID(ctx))
+		archived = cmdTag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ColumnModel.ArchiveTasks() -> %w", dbError(err))
+	}
+	return archived, nil
+}
+
+// GetByID - searching for column in DB by ID, returning finded Column with loaded tasks.
+// Archived tasks of column are loaded only if WithArchived is passed, WithQuery filters loaded tasks.
+func (cm ColumnModel) GetByID(ctx context.Context, columnID uint32, opts ...LoadOption) (Column, error) {
+	sql := "SELECT " + columnColumns + " FROM \"column\" WHERE column_id = $1;"
+
+	obtainedColumn, err := queryRow(ctx, cm.DB, columnFields, sql, columnID)
+
+	if err != nil {
+		return Column{}, fmt.Errorf("ColumnModel.GetByID() -> %w", dbError(err))
+	}
+
+	if o := newLoadOptions(opts); o.tasks {
+		obtainedColumn, err = cm.loadTasks(ctx, obtainedColumn, o)
+		if err != nil {
+			return Column{}, fmt.Errorf("ColumnModel.GetByID() -> %w", dbError(err))
+		}
+	}
+
+	return obtainedColumn, nil
+}
+
+// loadTasks - loading tasks in Column.Tasks slice in their order, only tasks matching o.query are loaded.
+// Archived tasks are loaded only if o.archived is set and follow active tasks.
+func (cm ColumnModel) loadTasks(ctx context.Context, column Column, o loadOptions) (Column, error) {
+	args := queryArgs{column.ID}
+	clauses := ("WHERE task.column_id = $1 AND task.deleted_at IS NULL AND " + o.tasksCondition(&args) + " " +
+		"ORDER BY task.archived_at IS NOT NULL, task_position")
+
+	tasks, err := TaskModel(cm).getMany(ctx, o, clauses, args...)
+	if err != nil {
+		return Column{}, fmt.Errorf("ColumnModel.loadTasks() -> %w", dbError(err))
+	}
+
+	column.Tasks = tasks
+	return column, nil
+}
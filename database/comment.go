@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Comment - comment model struct, comments are discussion of task.
+// Comment can be reply to other comment of the same task, replies are deleted together with it.
+type Comment struct {
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	ReplyToID *uint32       `json:"reply_to_id"` // optional, ID of comment this comment replies to
+	Text      string        `json:"comment_text"`
+	Author    CommentAuthor `json:"author"`
+	ID        uint32        `json:"comment_id"`
+	TaskID    uint32        `json:"task_id"`
+}
+
+// CommentAuthor - other name for SmallPerson struct, used for representing comment author in Comment struct.
+type CommentAuthor SmallPerson
+
+// CommentUpdate - fields to change in CommentModel.Update, nil fields are left unchanged.
+type CommentUpdate struct {
+	Text *string `json:"comment_text"`
+}
+
+// commentColumns - columns of comment table joined with author from person table, scanned by commentFields.
+const commentColumns = ("comment.comment_id, comment.task_id, comment.reply_to_id, comment.comment_text, " +
+	"comment.created_at, comment.updated_at, " + smallPersonColumns)
+
+// commentFields - returns destinations for scanning row selected by commentColumns into c.
+func commentFields(c *Comment) []any {
+	return append([]any{&c.ID, &c.TaskID, &c.ReplyToID, &c.Text, &c.CreatedAt, &c.UpdatedAt},
+		smallPersonFields((*SmallPerson)(&c.Author))...)
+}
+
+// commentSelectSQL - beginning of query for selecting comments with their authors.
+const commentSelectSQL = ("SELECT " + commentColumns + " " +
+	"FROM comment JOIN person ON person.person_id = comment.author_id ")
+
+// CommentModel - struct that implements CommentManager interface for interacting with comment table in db.
+type CommentModel struct {
+	DB DBConn
+}
+
+// Create - Creates new row in table 'comment' with values from `c` fields.
+// Returning created Comment.
+func (cm CommentModel) Create(ctx context.Context, c Comment) (Comment, error) {
+	sql := ("WITH inserted_comment AS (" +
+		"INSERT INTO comment (task_id, author_id, reply_to_id, comment_text) " +
+		"VALUES ($1, $2, $3, $4) " +
+		"RETURNING comment_id, task_id, author_id, reply_to_id, comment_text, created_at, updated_at) " +
+		"SELECT " + commentColumns + " " +
+		"FROM inserted_comment AS comment JOIN person ON person.person_id = comment.author_id;")
+
+	var createdComment Comment
+	err := inTx(ctx, cm.DB, func(tx pgx.Tx) error {
+		var err error
+		createdComment, err = queryRow(ctx, tx, commentFields, sql,
+			c.TaskID,
+			c.Author.ID,
+			c.ReplyToID,
+			c.Text,
+		)
+		return err
+	})
+
+	if err != nil {
+		return Comment{}, fmt.Errorf("CommentModel.Create() -> %w", dbError(err))
+	}
+	return createdComment, nil
+}
+
+// DeleteByID - deletes row from table 'comment', replies to comment are deleted too.
+func (cm CommentModel) DeleteByID(ctx context.Context, commentID uint32) error {
+	sql := "DELETE FROM comment WHERE comment_id = $1;"
+	err := inTx(ctx, cm.DB, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, sql, commentID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("CommentModel.DeleteByID() -> %w", dbError(err))
+	}
+	return nil
+}
+
+// Update - updates row in table 'comment' with non-nil fields of `update`.
+// Returning updated Comment.
+func (cm CommentModel) Update(ctx context.Context, commentID uint32, update CommentUpdate) (Comment, error) {
+	sql := ("UPDATE comment SET " +
+		"comment_text = COALESCE($2, comment_text) " +
+		"WHERE comment_id = $1 RETURNING comment_id;")
+
+	err := inTx(ctx, cm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, commentID, update.Text).Scan(&commentID)
+	})
+	if err != nil {
+		return Comment{}, fmt.Errorf("CommentModel.Update() -> %w", dbError(err))
+	}
+
+	updatedComment, err := cm.GetByID(ctx, commentID)
+	if err != nil {
+		return Comment{}, fmt.Errorf("CommentModel.Update() -> %w", dbError(err))
+	}
+	return updatedComment, nil
+}
+
+// GetByID - searching for comment in DB by ID, returning finded Comment.
+func (cm CommentModel) GetByID(ctx context.Context, commentID uint32) (Comment, error) {
+	sql := commentSelectSQL + "WHERE comment.comment_id = $1;"
+
+	obtainedComment, err := queryRow(ctx, cm.DB, commentFields, sql, commentID)
+
+	if err != nil {
+		return Comment{}, fmt.Errorf("CommentModel.GetByID() -> %w", dbError(err))
+	}
+	return obtainedComment, nil
+}
+
+// GetByTask - returns page of task comments in order of their creation,
+// replies are returned in the same list and are linked to comments by Comment.ReplyToID.
+func (cm CommentModel) GetByTask(ctx context.Context, taskID uint32, page Page) ([]Comment, error) {
+	clauses := ("WHERE comment.task_id = $1 AND comment.comment_id > $2 " +
+		"ORDER BY comment.comment_id LIMIT $3;")
+
+	comments, err := queryRows(ctx, cm.DB, commentFields, commentSelectSQL+clauses, taskID, page.AfterID, page.limit())
+	if err != nil {
+		return nil, fmt.Errorf("CommentModel.GetByTask() -> %w", dbError(err))
+	}
+	return comments, nil
+}
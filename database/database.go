@@ -3,6 +3,9 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -10,22 +13,49 @@ import (
 
 // DB - struct for interacting with database.
 type DB struct {
-	System SystemManager
-	Person PersonManager
-	Board  BoardManager
-	Task   TaskManager
-	Tag    TagManager
+	System   SystemManager
+	Person   PersonManager
+	Board    BoardManager
+	Task     TaskManager
+	Tag      TagManager
+	Column   ColumnManager
+	Session  SessionManager
+	Comment  CommentManager
+	Activity ActivityManager
+	Search   SearchManager
+
+	conn DBConn // connection shared by all managers, used to start transactions
 }
 
 // NewDB - returning new initilized DB.
 func NewDB(dbConn DBConn) DB {
 	return DB{
-		System: SystemModel{DB: dbConn},
-		Person: PersonModel{DB: dbConn},
-		Board:  BoardModel{DB: dbConn},
-		Task:   TaskModel{DB: dbConn},
-		Tag:    TagModel{DB: dbConn},
+		System:   SystemModel{DB: dbConn},
+		Person:   PersonModel{DB: dbConn},
+		Board:    BoardModel{DB: dbConn},
+		Task:     TaskModel{DB: dbConn},
+		Tag:      TagModel{DB: dbConn},
+		Column:   ColumnModel{DB: dbConn},
+		Session:  SessionModel{DB: dbConn},
+		Comment:  CommentModel{DB: dbConn},
+		Activity: ActivityModel{DB: dbConn},
+		Search:   SearchModel{DB: dbConn},
+		conn:     dbConn,
+	}
+}
+
+// WithTx - runs fn with DB, whose managers all work in one transaction. Transaction is committed
+// if fn returns nil, else rolled back together with everything done by fn.
+// WithTx called on DB passed to fn starts nested transaction on savepoint, so only its own
+// changes are rolled back if nested fn fails.
+func (db DB) WithTx(ctx context.Context, fn func(tx DB) error) error {
+	if db.conn == nil {
+		return errors.New("DB.WithTx() -> DB is not initialized by NewDB")
 	}
+
+	return inTx(ctx, db.conn, func(tx pgx.Tx) error {
+		return fn(NewDB(tx))
+	})
 }
 
 // DBConn - interface for data models to interact with db.
@@ -33,52 +63,145 @@ type DBConn interface {
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// inTx - runs fn in transaction started on dbConn, commits transaction if fn returns nil, else rollbacks it.
+// Changes made in transaction are logged to activity as made by person set by WithActor in ctx,
+// so every method changing board data runs in transaction.
+func inTx(ctx context.Context, dbConn DBConn, fn func(tx pgx.Tx) error) error {
+	tx, err := dbConn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("inTx() -> %w", dbError(err))
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // returns ErrTxClosed after successful commit
+
+	if err := setActivityActor(ctx, tx); err != nil {
+		return fmt.Errorf("inTx() -> %w", dbError(err))
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("inTx() -> %w", dbError(err))
+	}
+	return nil
 }
 
 // SystemManager - interface for interacting with db structure.
 type SystemManager interface {
 	RecreateAllTables(ctx context.Context) error
 	IsTableExist(ctx context.Context, tableName string) (bool, error)
+	MigrateUp(ctx context.Context) (int, error)
+	MigrateDown(ctx context.Context, steps int) (int, error)
+	SchemaVersion(ctx context.Context) (uint32, error)
 }
 
 // PersonManager - interface for interacting with person table in db.
 type PersonManager interface {
 	Create(ctx context.Context, person Person) (Person, error)
-	DeleteByID(ctx context.Context, personID uint32) error
-	GetByID(ctx context.Context, personID uint32) (Person, error)
-	GetByEmail(ctx context.Context, email string) (Person, error)
-	GetByUsername(ctx context.Context, username string) (Person, error)
+	Update(ctx context.Context, personID uint32, update PersonUpdate) (Person, error)
+	UpdatePasswordHash(ctx context.Context, personID uint32, passwordHash string) error
+	DeleteByID(ctx context.Context, personID uint32, successors map[uint32]uint32) error
+	GetByID(ctx context.Context, personID uint32, opts ...LoadOption) (Person, error)
+	GetSmallByID(ctx context.Context, personID uint32) (SmallPerson, error)
+	GetByEmail(ctx context.Context, email string, opts ...LoadOption) (Person, error)
+	GetByUsername(ctx context.Context, username string, opts ...LoadOption) (Person, error)
 }
 
 // BoardManager - interface for interacting with board table in db.
 type BoardManager interface {
 	Create(ctx context.Context, board Board) (Board, error)
+	Update(ctx context.Context, boardID uint32, update BoardUpdate) (Board, error)
 	DeleteByID(ctx context.Context, boardID uint32) error
-	GetByID(ctx context.Context, boardID uint32) (Board, error)
+	Restore(ctx context.Context, boardID, ownerID uint32) (Board, error)
+	GetDeleted(ctx context.Context, ownerID uint32) ([]Board, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+	Archive(ctx context.Context, boardID uint32) (Board, error)
+	Unarchive(ctx context.Context, boardID uint32) (Board, error)
+	GetByID(ctx context.Context, boardID uint32, opts ...LoadOption) (Board, error)
+	GetSmallByID(ctx context.Context, boardID uint32) (SmallBoard, error)
 	AddContributorToBoard(ctx context.Context, contrib Contributor, board Board) (Board, error)
+	SetContributorRole(ctx context.Context, contrib Contributor, board Board) (Board, error)
+	TransferOwnership(ctx context.Context, board Board, newOwner Contributor) (Board, error)
 	RemoveContributorFromBoard(ctx context.Context, contrib Contributor, board Board) (Board, error)
 	AddTaskToBoard(ctx context.Context, task Task, board Board) (Board, error)
 	RemoveTaskFromBoard(ctx context.Context, task Task, board Board) (Board, error)
 	AddTagToBoard(ctx context.Context, tag Tag, board Board) (Board, error)
 	RemoveTagFromBoard(ctx context.Context, tag Tag, board Board) (Board, error)
+	AddColumnToBoard(ctx context.Context, column Column, board Board) (Board, error)
+	RemoveColumnFromBoard(ctx context.Context, column Column, board Board) (Board, error)
 }
 
 // TaskManager - interface for interacting with task table in db.
 type TaskManager interface {
 	Create(ctx context.Context, task Task) (Task, error)
+	Update(ctx context.Context, taskID uint32, update TaskUpdate) (Task, error)
 	DeleteByID(ctx context.Context, taskID uint32) error
-	GetByID(ctx context.Context, taskID uint32) (Task, error)
+	Restore(ctx context.Context, boardID, taskID uint32) (Task, error)
+	GetDeleted(ctx context.Context, boardID uint32) ([]Task, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+	Archive(ctx context.Context, taskID uint32) (Task, error)
+	Unarchive(ctx context.Context, taskID uint32) (Task, error)
+	GetByID(ctx context.Context, taskID uint32, opts ...LoadOption) (Task, error)
+	GetDueBetween(ctx context.Context, boardID uint32, from, to time.Time) ([]Task, error)
+	GetOverdueByAssignee(ctx context.Context, personID uint32, at time.Time) ([]Task, error)
+	GetByFilter(ctx context.Context, filter TaskFilter) ([]Task, error)
 	AddTagToTask(ctx context.Context, tag Tag, task Task) (Task, error)
 	RemoveTagFromTask(ctx context.Context, tag Tag, task Task) (Task, error)
 	AddAssigneeToTask(ctx context.Context, assignee TaskAssignee, task Task) (Task, error)
 	RemoveAssignFromTask(ctx context.Context, person TaskAssignee, task Task) (Task, error)
 	AddSubtaskToTask(ctx context.Context, subtask Subtask, task Task) (Task, error)
 	RemoveSubtaskFromTask(ctx context.Context, subtask Subtask, task Task) (Task, error)
+	UpdateSubtask(ctx context.Context, subtask Subtask, task Task, update SubtaskUpdate) (Task, error)
+	MoveSubtask(ctx context.Context, subtask Subtask, task Task, position int) (Task, error)
+	Move(ctx context.Context, task Task, column Column, position int) (Task, error)
 }
 
 // TagManager - interface for interacting with tag table in db.
 type TagManager interface {
 	Create(ctx context.Context, tag Tag) (Tag, error)
+	Update(ctx context.Context, tagID uint32, update TagUpdate) (Tag, error)
 	DeleteByID(ctx context.Context, tagID uint32) error
 	GetByID(ctx context.Context, tagID uint32) (Tag, error)
 }
+
+// ColumnManager - interface for interacting with column table in db.
+type ColumnManager interface {
+	Create(ctx context.Context, column Column) (Column, error)
+	Update(ctx context.Context, columnID uint32, update ColumnUpdate) (Column, error)
+	DeleteByID(ctx context.Context, columnID uint32) error
+	ArchiveTasks(ctx context.Context, columnID uint32) (int64, error)
+	GetByID(ctx context.Context, columnID uint32, opts ...LoadOption) (Column, error)
+}
+
+// CommentManager - interface for interacting with comment table in db.
+type CommentManager interface {
+	Create(ctx context.Context, comment Comment) (Comment, error)
+	Update(ctx context.Context, commentID uint32, update CommentUpdate) (Comment, error)
+	DeleteByID(ctx context.Context, commentID uint32) error
+	GetByID(ctx context.Context, commentID uint32) (Comment, error)
+	GetByTask(ctx context.Context, taskID uint32, page Page) ([]Comment, error)
+}
+
+// ActivityManager - interface for reading activity log from activity table in db.
+type ActivityManager interface {
+	GetByBoard(ctx context.Context, boardID uint32, page Page) ([]Activity, error)
+	GetByTask(ctx context.Context, boardID, taskID uint32, page Page) ([]Activity, error)
+}
+
+// SearchManager - interface for full-text search of tasks in db.
+type SearchManager interface {
+	Search(ctx context.Context, q SearchQuery) ([]SearchResult, error)
+}
+
+// SessionManager - interface for interacting with session table in db.
+type SessionManager interface {
+	Create(ctx context.Context, session Session) (Session, error)
+	GetActiveByTokenHash(ctx context.Context, tokenHash string) (Session, error)
+	RevokeByID(ctx context.Context, sessionID uint32) error
+	RevokeAllByPersonID(ctx context.Context, personID uint32) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
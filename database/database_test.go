@@ -4,19 +4,34 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var DBURL = os.Getenv("TEST_DB_URL")
 var db DB
 
+// ignoreTimestamps - ignores timestamps set by db, which mocked data doesn't have.
+var ignoreTimestamps = cmp.Options{
+	cmpopts.IgnoreFields(Person{}, "CreatedAt", "UpdatedAt"),
+	cmpopts.IgnoreFields(Board{}, "CreatedAt", "UpdatedAt"),
+	cmpopts.IgnoreFields(Task{}, "CreatedAt", "UpdatedAt"),
+	cmpopts.IgnoreFields(Subtask{}, "CreatedAt", "UpdatedAt"),
+	cmpopts.IgnoreFields(Tag{}, "CreatedAt", "UpdatedAt"),
+}
+
 type MockedData struct {
 	Persons  []Person  `json:"persons"`
 	Boards   []Board   `json:"boards"`
@@ -49,6 +64,18 @@ func LoadMockData() (MockedData, error) {
 	if err := json.Unmarshal(jsonData, &mockedData); err != nil {
 		return MockedData{}, fmt.Errorf("LoadMockData() -> %w", err)
 	}
+
+	// emails of board owners and task authors aren't unmarshaled, so they are taken from persons
+	emails := make(map[uint32]string, len(mockedData.Persons))
+	for _, person := range mockedData.Persons {
+		emails[person.ID] = person.Email
+	}
+	for i := range mockedData.Boards {
+		mockedData.Boards[i].Owner.Email = emails[mockedData.Boards[i].Owner.ID]
+	}
+	for i := range mockedData.Tasks {
+		mockedData.Tasks[i].Author.Email = emails[mockedData.Tasks[i].Author.ID]
+	}
 	return mockedData, nil
 }
 
@@ -115,7 +142,8 @@ func TestSystemRecreateAllTables(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tables := []string{"person", "board", "task", "subtask", "tag", "task_tag", "contributor"}
+	tables := []string{"person", "board", "column", "task", "subtask", "tag", "task_tag", "contributor",
+		"session", "comment", "activity", "task_search", "schema_migrations"}
 	for _, table := range tables {
 		if isExist, err := db.System.IsTableExist(ctx, table); err != nil {
 			t.Error(err)
@@ -162,7 +190,7 @@ func TestPersonCreate(t *testing.T) {
 			t.Error(err)
 		}
 
-		if !cmp.Equal(createdPerson, mockedPerson, cmpIgnore) {
+		if !cmp.Equal(createdPerson, mockedPerson, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Created person not equal to mocked: \n\t%v \n\t%v",
 				createdPerson, mockedPerson)
 		}
@@ -203,7 +231,7 @@ func TestPersonGetByID(t *testing.T) {
 
 		t.Logf("Obtained: %v", obtainedPerson)
 
-		if !cmp.Equal(obtainedPerson, mockedPerson, cmpIgnore) {
+		if !cmp.Equal(obtainedPerson, mockedPerson, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Obtained person not equal to mocked: \n\t%v \n\t%v",
 				obtainedPerson, mockedPerson)
 		}
@@ -238,7 +266,7 @@ func TestPersonGetByEmail(t *testing.T) {
 
 		t.Logf("Obtained: %v", obtainedPerson)
 
-		if !cmp.Equal(obtainedPerson, mockedPerson, cmpIgnore) {
+		if !cmp.Equal(obtainedPerson, mockedPerson, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Obtained person not equal to mocked: \n\t%v \n\t%v",
 				obtainedPerson, mockedPerson)
 		}
@@ -273,7 +301,7 @@ func TestPersonGetByUsername(t *testing.T) {
 
 		t.Logf("Obtained: %v", obtainedPerson)
 
-		if !cmp.Equal(obtainedPerson, mockedPerson, cmpIgnore) {
+		if !cmp.Equal(obtainedPerson, mockedPerson, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Obtained person not equal to mocked: \n\t%v \n\t%v",
 				obtainedPerson, mockedPerson)
 		}
@@ -295,7 +323,7 @@ func TestBoardCreate(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	cmpIgnore := cmpopts.IgnoreFields(Board{}, "Contributors", "Tasks", "Tags")
+	cmpIgnore := cmpopts.IgnoreFields(Board{}, "Contributors", "Columns", "Tasks", "Tags")
 	for _, board := range mockedData.Boards {
 		t.Logf("%v", board)
 		createdBoard, err := db.Board.Create(ctx, board)
@@ -303,7 +331,7 @@ func TestBoardCreate(t *testing.T) {
 			t.Error(err)
 		}
 
-		if !cmp.Equal(createdBoard, board, cmpIgnore) {
+		if !cmp.Equal(createdBoard, board, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Created board not equal to mocked: \n\t%v \n\t%v",
 				createdBoard, board)
 		}
@@ -337,14 +365,14 @@ func TestBoardGetByID(t *testing.T) {
 		t.Error("Searching for board with non-existent boardID not throwing error")
 	}
 
-	cmpIgnore := cmpopts.IgnoreFields(Board{}, "Contributors", "Tasks", "Tags")
+	cmpIgnore := cmpopts.IgnoreFields(Board{}, "Contributors", "Columns", "Tasks", "Tags")
 	for _, mockedBoard := range mockedData.Boards {
 		obtainedBoard, err := db.Board.GetByID(ctx, mockedBoard.ID)
 		if err != nil {
 			t.Error(err)
 		}
 
-		if !cmp.Equal(obtainedBoard, mockedBoard, cmpIgnore) {
+		if !cmp.Equal(obtainedBoard, mockedBoard, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Obtained board not equal to mocked: \n\t%v \n\t%v",
 				obtainedBoard, mockedBoard)
 		}
@@ -369,14 +397,14 @@ func TestTaskCreate(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	cmpIgnore := cmpopts.IgnoreFields(Task{}, "Subtasks", "Tags", "Assignees")
+	cmpIgnore := cmpopts.IgnoreFields(Task{}, "Subtasks", "Tags", "Assignees", "ColumnID", "Position")
 	for _, mockedTask := range mockedData.Tasks {
 		createdTask, err := db.Task.Create(ctx, mockedTask)
 		if err != nil {
 			t.Error(err)
 		}
 
-		if !cmp.Equal(createdTask, mockedTask, cmpIgnore) {
+		if !cmp.Equal(createdTask, mockedTask, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Created task not equal to mocked: \n\t%v \n\t%v",
 				createdTask, mockedTask)
 		}
@@ -408,14 +436,14 @@ func TestTaskGetByID(t *testing.T) {
 		t.Error("Searching for task with non-existent taskID not throwing error")
 	}
 
-	cmpIgnore := cmpopts.IgnoreFields(Task{}, "Subtasks", "Tags")
+	cmpIgnore := cmpopts.IgnoreFields(Task{}, "Subtasks", "Tags", "ColumnID", "Position")
 	for _, mockedTask := range mockedData.Tasks {
 		obtainedTask, err := db.Task.GetByID(ctx, mockedTask.ID)
 		if err != nil {
 			t.Error(err)
 		}
 
-		if !cmp.Equal(obtainedTask, mockedTask, cmpIgnore) {
+		if !cmp.Equal(obtainedTask, mockedTask, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Obtained task not equal to mocked: \n\t%v \n\t%v",
 				obtainedTask, mockedTask)
 		}
@@ -449,7 +477,7 @@ func TestTagCreate(t *testing.T) {
 			t.Error(err)
 		}
 
-		if !cmp.Equal(mockedTag, createdTag, cmpIgnore) {
+		if !cmp.Equal(mockedTag, createdTag, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Created tag not equal to mocked: \n\t%v \n\t%v",
 				createdTag, mockedTag)
 		}
@@ -490,7 +518,7 @@ func TestTagGetByID(t *testing.T) {
 			t.Error(err)
 		}
 
-		if !cmp.Equal(obtainedTag, mockedTag, cmpIgnore) {
+		if !cmp.Equal(obtainedTag, mockedTag, cmpIgnore, ignoreTimestamps) {
 			t.Errorf("Obtained task not equal to mocked: \n\t%v \n\t%v",
 				obtainedTag, mockedTag)
 		}
@@ -542,7 +570,7 @@ OuterFor:
 
 		for _, tag := range task.Tags {
 			if tag.ID == taskTag.TagID {
-				if !cmp.Equal(tag, mockedTag) {
+				if !cmp.Equal(tag, mockedTag, ignoreTimestamps) {
 					t.Errorf("Obtained tag not equal to mocked: \n\t%v \n\t%v",
 						tag, mockedTag)
 				} else {
@@ -637,7 +665,7 @@ OuterFor:
 
 		for _, subtask := range task.Subtasks {
 			if subtask.ID == mockedSubtask.ID {
-				if !cmp.Equal(subtask, mockedSubtask) {
+				if !cmp.Equal(subtask, mockedSubtask, ignoreTimestamps) {
 					t.Errorf("Obtained subtask not equal to mocked: \n\t%v \n\t%v",
 						subtask, mockedSubtask)
 				} else {
@@ -681,7 +709,7 @@ OuterFor:
 
 		for _, tag := range board.Tags {
 			if tag.ID == mockedTag.ID {
-				if !cmp.Equal(tag, mockedTag) {
+				if !cmp.Equal(tag, mockedTag, ignoreTimestamps) {
 					t.Errorf("Added tag not equal to mocked: \n\t%v \n\t%v",
 						tag, mockedTag)
 				} else {
@@ -723,9 +751,10 @@ OuterFor:
 			t.Error(err)
 		}
 
+		cmpIgnore := cmpopts.IgnoreFields(Task{}, "ColumnID", "Position")
 		for _, task := range board.Tasks {
 			if task.ID == mockedTask.ID {
-				if !cmp.Equal(task, mockedTask) {
+				if !cmp.Equal(task, mockedTask, cmpIgnore, ignoreTimestamps) {
 					t.Errorf("Added task not equal to mocked: \n\t%v \n\t%v",
 						task, mockedTask)
 				} else {
@@ -767,7 +796,7 @@ OuterFor:
 			t.Error(err)
 		}
 
-		board, err = db.Board.AddContributorToBoard(ctx, Contributor(person.Small()), board)
+		board, err = db.Board.AddContributorToBoard(ctx, person.AsContributor(ContributorMember), board)
 		if err != nil {
 			t.Error(err)
 		}
@@ -835,7 +864,7 @@ OuterFor:
 		}
 		for _, task := range person.AssignedTasks {
 			if task.ID == assignRow.TaskID {
-				if !cmp.Equal(task, mockedTask) {
+				if !cmp.Equal(task, mockedTask, ignoreTimestamps) {
 					t.Errorf("Loaded assigned task not equal to mocked: \n\t%v \n\t%v",
 						task, mockedTask)
 				} else {
@@ -879,7 +908,7 @@ func TestPersonLoadBoards(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		board, err = db.Board.AddContributorToBoard(ctx, Contributor(person.Small()), board)
+		board, err = db.Board.AddContributorToBoard(ctx, person.AsContributor(ContributorMember), board)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -962,13 +991,13 @@ func TestPersonDeleteByID(t *testing.T) {
 	}
 
 	for _, mockedPerson := range mockedData.Persons {
-		err := db.Person.DeleteByID(ctx, mockedPerson.ID)
+		err := db.Person.DeleteByID(ctx, mockedPerson.ID, nil)
 		if err != nil {
 			t.Error(err)
 		}
 	}
 
-	if err := db.Person.DeleteByID(ctx, 131); err != nil {
+	if err := db.Person.DeleteByID(ctx, 131, nil); err != nil {
 		t.Error("Person.DeleteByID() not throwing error when deleting non-existent person")
 	}
 }
@@ -1295,7 +1324,7 @@ func TestBoardRRemoveContributorFromBoard(t *testing.T) {
 	for _, contributor := range mockedData.Contributors {
 		board, _ := db.Board.GetByID(ctx, contributor.BoardID)
 		person, _ := db.Person.GetByID(ctx, contributor.PersonID)
-		db.Board.AddContributorToBoard(ctx, Contributor(person.Small()), board)
+		db.Board.AddContributorToBoard(ctx, person.AsContributor(ContributorMember), board)
 	}
 
 	var boards []Board
@@ -1379,3 +1408,2496 @@ func TestBoardRemoveTagFromBoard(t *testing.T) {
 		}
 	}
 }
+
+func TestBoardCreateDefaultColumns(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mockedBoard := range mockedData.Boards {
+		board, err := db.Board.GetByID(ctx, mockedBoard.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(board.Columns) != len(DefaultColumns) {
+			t.Fatalf("Board created with %d columns, expected %d", len(board.Columns), len(DefaultColumns))
+		}
+
+		for i, column := range board.Columns {
+			if column.Name != DefaultColumns[i] || column.BoardID != board.ID {
+				t.Errorf("Default column not equal to expected: \n\t%v \n\t%v", column, DefaultColumns[i])
+			}
+		}
+	}
+}
+
+func TestBoardAddColumnToBoard(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.GetByID(ctx, mockedData.Boards[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	board, err = db.Board.AddColumnToBoard(ctx, Column{Name: "Review"}, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastColumn := board.Columns[len(board.Columns)-1]
+	if lastColumn.Name != "Review" {
+		t.Errorf("Added column is not the last board column: %v", board.Columns)
+	}
+
+	obtainedColumn, err := db.Column.GetByID(ctx, lastColumn.ID)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !cmp.Equal(obtainedColumn, lastColumn, ignoreTimestamps) {
+		t.Errorf("Obtained column not equal to added: \n\t%v \n\t%v", obtainedColumn, lastColumn)
+	}
+}
+
+func TestBoardGetByIDGroupsTasksByColumn(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.GetByID(ctx, mockedData.Boards[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	author := TaskAuthor(board.Owner)
+	doneColumn := board.Columns[len(board.Columns)-1]
+
+	for _, name := range []string{"first", "second", "third"} {
+		board, err = db.Board.AddTaskToBoard(ctx, Task{Name: name, Author: author, ColumnID: doneColumn.ID}, board)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	board, err = db.Board.AddTaskToBoard(ctx, Task{Name: "todo", Author: author}, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(board.Columns[0].Tasks) != 1 || board.Columns[0].Tasks[0].Name != "todo" {
+		t.Errorf("Task without column not placed to first column: %v", board.Columns[0].Tasks)
+	}
+
+	doneColumn = board.Columns[len(board.Columns)-1]
+	if len(doneColumn.Tasks) != 3 {
+		t.Fatalf("Expected 3 tasks in column, got: %v", doneColumn.Tasks)
+	}
+	for i, name := range []string{"first", "second", "third"} {
+		if doneColumn.Tasks[i].Name != name {
+			t.Errorf("Column tasks are not in insertion order: %v", doneColumn.Tasks)
+		}
+	}
+
+	if _, err := db.Board.RemoveColumnFromBoard(ctx, doneColumn, board); !errors.Is(err, ErrConflict) {
+		t.Errorf("Column with tasks removed, expected ErrConflict, got: %v", err)
+	}
+
+	for _, task := range doneColumn.Tasks {
+		if err := db.Task.DeleteByID(ctx, task.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Board.RemoveColumnFromBoard(ctx, doneColumn, board); !errors.Is(err, ErrConflict) {
+		t.Errorf("Column with tasks in trash removed, expected ErrConflict, got: %v", err)
+	}
+
+	restoredTask, err := db.Task.Restore(ctx, board.ID, doneColumn.Tasks[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restoredTask.ColumnID != doneColumn.ID {
+		t.Errorf("Task restored to column %d, expected %d", restoredTask.ColumnID, doneColumn.ID)
+	}
+
+	if err := db.Task.DeleteByID(ctx, restoredTask.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.PurgeDeleted(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	board, err = db.Board.RemoveColumnFromBoard(ctx, doneColumn, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(board.Columns) != len(DefaultColumns)-1 || len(board.Tasks) != 1 {
+		t.Errorf("Column with purged tasks not removed: %v", board.Columns)
+	}
+}
+
+func TestTaskMove(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.GetByID(ctx, mockedData.Boards[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		board, err = db.Board.AddTaskToBoard(ctx, Task{Name: name, Author: TaskAuthor(board.Owner)}, board)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	columnTaskNames := func(column Column) string {
+		var names string
+		for _, task := range column.Tasks {
+			names += task.Name
+		}
+		return names
+	}
+
+	todo, done := board.Columns[0], board.Columns[len(board.Columns)-1]
+	moves := []struct {
+		task     string
+		todo     string
+		done     string
+		column   Column
+		position int
+	}{
+		{"d", "dabc", "", todo, 0},
+		{"a", "dbca", "", todo, 3},
+		{"b", "dca", "b", done, 0},
+		{"c", "da", "bc", done, 5},
+		{"a", "d", "bac", done, 1},
+		{"d", "d", "bac", todo, 10},
+	}
+
+	for _, move := range moves {
+		var taskToMove Task
+		for _, task := range board.Tasks {
+			if task.Name == move.task {
+				taskToMove = task
+			}
+		}
+
+		movedTask, err := db.Task.Move(ctx, taskToMove, move.column, move.position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if movedTask.ColumnID != move.column.ID {
+			t.Errorf("Task not moved to column %d: %v", move.column.ID, movedTask)
+		}
+
+		board, err = db.Board.GetByID(ctx, board.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := columnTaskNames(board.Columns[0]); got != move.todo {
+			t.Errorf("Wrong order of first column after moving %s: %s != %s", move.task, got, move.todo)
+		}
+		if got := columnTaskNames(board.Columns[len(board.Columns)-1]); got != move.done {
+			t.Errorf("Wrong order of last column after moving %s: %s != %s", move.task, got, move.done)
+		}
+	}
+
+	otherBoard, err := db.Board.GetByID(ctx, mockedData.Boards[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.Move(ctx, board.Tasks[0], otherBoard.Columns[0], 0); err == nil {
+		t.Error("TaskModel.Move() does't throw error when moving task to column of other board")
+	}
+}
+
+func TestTaskMoveRebalance(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.GetByID(ctx, mockedData.Boards[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		board, err = db.Board.AddTaskToBoard(ctx, Task{Name: fmt.Sprint(i), Author: TaskAuthor(board.Owner)}, board)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every move to the second place halves the gap after first task,
+	// so column has to be renumbered at some point.
+	for i := 0; i < 40; i++ {
+		column := board.Columns[0]
+		lastTask := column.Tasks[len(column.Tasks)-1]
+		if _, err := db.Task.Move(ctx, lastTask, column, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		board, err = db.Board.GetByID(ctx, board.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		column = board.Columns[0]
+		if column.Tasks[1].ID != lastTask.ID {
+			t.Fatalf("Task not moved to second place: %v", column.Tasks)
+		}
+		for j := 1; j < len(column.Tasks); j++ {
+			if column.Tasks[j-1].Position >= column.Tasks[j].Position {
+				t.Fatalf("Column positions are not strictly increasing: %v", column.Tasks)
+			}
+		}
+	}
+}
+
+func TestColumnCreateConcurrent(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	const columnsCount = 10
+	boardID := mockedData.Boards[0].ID
+	errs := make(chan error, columnsCount)
+	for i := 0; i < columnsCount; i++ {
+		go func(name string) {
+			_, err := db.Column.Create(ctx, Column{Name: name, BoardID: boardID})
+			errs <- err
+		}(fmt.Sprint(i))
+	}
+	for i := 0; i < columnsCount; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+
+	board, err := db.Board.GetByID(ctx, boardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, column := range board.Columns {
+		if column.Position != uint32(i+1) {
+			t.Errorf("Concurrently created columns have positions %v, expected unique positions in a row",
+				board.Columns)
+			break
+		}
+	}
+}
+
+func TestTaskMoveConcurrent(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.GetByID(ctx, mockedData.Boards[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		board, err = db.Board.AddTaskToBoard(ctx, Task{Name: fmt.Sprint(i), Author: TaskAuthor(board.Owner)}, board)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs := make(chan error, len(board.Tasks))
+	for i, task := range board.Tasks {
+		go func(task Task, column Column) {
+			_, err := db.Task.Move(ctx, task, column, 0)
+			errs <- err
+		}(task, board.Columns[i%2])
+	}
+	for range board.Tasks {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+
+	board, err = db.Board.GetByID(ctx, board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasksCount := 0
+	for _, column := range board.Columns {
+		tasksCount += len(column.Tasks)
+		for j := 1; j < len(column.Tasks); j++ {
+			if column.Tasks[j-1].Position >= column.Tasks[j].Position {
+				t.Errorf("Column positions are not strictly increasing: %v", column.Tasks)
+			}
+		}
+	}
+	if tasksCount != 10 {
+		t.Errorf("Tasks lost after concurrent moves: %d != 10", tasksCount)
+	}
+}
+
+func TestPersonUpdate(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	firstName := "Updated"
+	for _, mockedPerson := range mockedData.Persons {
+		updatedPerson, err := db.Person.Update(ctx, mockedPerson.ID, PersonUpdate{FirstName: &firstName})
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockedPerson.FirstName = firstName
+		cmpIgnore := cmpopts.IgnoreFields(Person{}, "Boards", "AssignedTasks")
+		if !cmp.Equal(updatedPerson, mockedPerson, cmpIgnore, ignoreTimestamps) {
+			t.Errorf("Updated person not equal to expected: \n\t%v \n\t%v",
+				updatedPerson, mockedPerson)
+		}
+	}
+
+	if _, err := db.Person.Update(ctx, 1337, PersonUpdate{FirstName: &firstName}); err == nil {
+		t.Error("PersonModel.Update() does't throw error when updating non-existent person")
+	}
+
+	takenUsername := mockedData.Persons[0].Username
+	if _, err := db.Person.Update(ctx, mockedData.Persons[1].ID, PersonUpdate{Username: &takenUsername}); err == nil {
+		t.Error("PersonModel.Update() does't throw error when updating UNIQUE field to taken value")
+	}
+}
+
+func TestBoardUpdate(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	name := "Renamed"
+	cmpIgnore := cmpopts.IgnoreFields(Board{}, "Contributors", "Columns", "Tasks", "Tags")
+	for _, mockedBoard := range mockedData.Boards {
+		updatedBoard, err := db.Board.Update(ctx, mockedBoard.ID, BoardUpdate{Name: &name})
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockedBoard.Name = name
+		if !cmp.Equal(updatedBoard, mockedBoard, cmpIgnore, ignoreTimestamps) {
+			t.Errorf("Updated board not equal to expected: \n\t%v \n\t%v", updatedBoard, mockedBoard)
+		}
+
+		unchangedBoard, err := db.Board.Update(ctx, mockedBoard.ID, BoardUpdate{})
+		if err != nil {
+			t.Error(err)
+		}
+		if !cmp.Equal(unchangedBoard, updatedBoard) {
+			t.Errorf("Empty update changed board: \n\t%v \n\t%v", unchangedBoard, updatedBoard)
+		}
+	}
+
+	if _, err := db.Board.Update(ctx, 1337, BoardUpdate{Name: &name}); err == nil {
+		t.Error("BoardModel.Update() does't throw error when updating non-existent board")
+	}
+}
+
+func TestTaskUpdate(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedTasks(); err != nil {
+		t.Fatal(err)
+	}
+
+	description := "Updated description"
+	cmpIgnore := cmpopts.IgnoreFields(Task{}, "Subtasks", "Tags", "Assignees", "ColumnID", "Position")
+	for _, mockedTask := range mockedData.Tasks {
+		updatedTask, err := db.Task.Update(ctx, mockedTask.ID, TaskUpdate{Description: &description})
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockedTask.Description = description
+		if !cmp.Equal(updatedTask, mockedTask, cmpIgnore, ignoreTimestamps) {
+			t.Errorf("Updated task not equal to expected: \n\t%v \n\t%v", updatedTask, mockedTask)
+		}
+	}
+
+	if _, err := db.Task.Update(ctx, 1337, TaskUpdate{Description: &description}); err == nil {
+		t.Error("TaskModel.Update() does't throw error when updating non-existent task")
+	}
+}
+
+func TestTagUpdate(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedTags(); err != nil {
+		t.Fatal(err)
+	}
+
+	name := "renamed"
+	for _, mockedTag := range mockedData.Tags {
+		updatedTag, err := db.Tag.Update(ctx, mockedTag.ID, TagUpdate{Name: &name})
+		if err != nil {
+			t.Error(err)
+		}
+
+		mockedTag.Name = name
+		if !cmp.Equal(updatedTag, mockedTag, ignoreTimestamps) {
+			t.Errorf("Updated tag not equal to expected: \n\t%v \n\t%v", updatedTag, mockedTag)
+		}
+	}
+
+	if _, err := db.Tag.Update(ctx, 1337, TagUpdate{Name: &name}); err == nil {
+		t.Error("TagModel.Update() does't throw error when updating non-existent tag")
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	person := mockedData.Persons[0]
+	session, err := db.Session.Create(ctx, Session{
+		TokenHash: "hash",
+		PersonID:  person.ID,
+		Kind:      SessionCookie,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obtainedSession, err := db.Session.GetActiveByTokenHash(ctx, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obtainedSession.ID != session.ID || obtainedSession.PersonID != person.ID {
+		t.Errorf("Obtained session not equal to created: \n\t%v \n\t%v", obtainedSession, session)
+	}
+
+	if _, err := db.Session.Create(ctx, Session{
+		TokenHash: "expired",
+		PersonID:  person.ID,
+		Kind:      SessionToken,
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Session.GetActiveByTokenHash(ctx, "expired"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SessionModel.GetActiveByTokenHash() returned %v for expired session, expected ErrNotFound", err)
+	}
+
+	if err := db.Session.RevokeByID(ctx, session.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Session.GetActiveByTokenHash(ctx, "hash"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SessionModel.GetActiveByTokenHash() returned %v for revoked session, expected ErrNotFound", err)
+	}
+
+	deleted, err := db.Session.DeleteExpired(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Errorf("SessionModel.DeleteExpired() deleted %d sessions, expected 2", deleted)
+	}
+
+	if _, err := db.Session.Create(ctx, Session{
+		TokenHash: "before password change",
+		PersonID:  person.ID,
+		Kind:      SessionToken,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Person.UpdatePasswordHash(ctx, person.ID, "new hash"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Session.GetActiveByTokenHash(ctx, "before password change")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("SessionModel.GetActiveByTokenHash() returned %v after password change, expected ErrNotFound", err)
+	}
+}
+
+func TestBoardSetContributorRole(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mockedContributor := range mockedData.Contributors {
+		board, err := db.Board.GetByID(ctx, mockedContributor.BoardID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		person, err := db.Person.GetByID(ctx, mockedContributor.PersonID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		board, err = db.Board.AddContributorToBoard(ctx, person.AsContributor(""), board)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		board, err = db.Board.SetContributorRole(ctx, person.AsContributor(ContributorViewer), board)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, contributor := range board.Contributors {
+			if contributor.ID == person.ID && contributor.Role != ContributorViewer {
+				t.Errorf("Contributor role not changed: \n\t%v", contributor)
+			}
+		}
+
+		if _, err := db.Board.SetContributorRole(ctx, person.AsContributor("superuser"), board); err == nil {
+			t.Error("BoardModel.SetContributorRole() does't throw error for unknown role")
+		}
+	}
+
+	board, err := db.Board.GetByID(ctx, mockedData.Boards[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Board.SetContributorRole(ctx, Contributor{ID: 1337, Role: ContributorAdmin},
+		board); !errors.Is(err, ErrNotFound) {
+		t.Errorf("BoardModel.SetContributorRole() returned %v for non-contributor, expected ErrNotFound", err)
+	}
+}
+
+func TestBoardTransferOwnership(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedContributor := mockedData.Contributors[0]
+	board, err := db.Board.GetByID(ctx, mockedContributor.BoardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	person, err := db.Person.GetByID(ctx, mockedContributor.PersonID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Board.TransferOwnership(ctx, board, person.AsContributor("")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("BoardModel.TransferOwnership() returned %v for non-contributor, expected ErrNotFound", err)
+	}
+
+	board, err = db.Board.AddContributorToBoard(ctx, person.AsContributor(ContributorViewer), board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldOwner := board.Owner
+	board, err = db.Board.TransferOwnership(ctx, board, person.AsContributor(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if board.Owner.ID != person.ID {
+		t.Errorf("Board owner not changed: \n\t%v \n\t%v", board.Owner, person)
+	}
+
+	expectedContributors := []Contributor{{Username: oldOwner.Username, FirstName: oldOwner.FirstName,
+		LastName: oldOwner.LastName, Email: oldOwner.Email, Role: ContributorAdmin, ID: oldOwner.ID}}
+	if !cmp.Equal(board.Contributors, expectedContributors) {
+		t.Errorf("Board contributors not equal to expected: \n\t%v \n\t%v", board.Contributors, expectedContributors)
+	}
+}
+
+func TestPersonDeleteByIDTransfersBoards(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedTasks(); err != nil {
+		t.Fatal(err)
+	}
+
+	board := mockedData.Boards[0]
+	var member, admin, outsider Person
+	for _, p := range mockedData.Persons {
+		if p.ID == board.Owner.ID {
+			continue
+		}
+		switch {
+		case member.ID == 0:
+			member = p
+		case admin.ID == 0:
+			admin = p
+		case outsider.ID == 0:
+			outsider = p
+		}
+	}
+
+	fullBoard, err := db.Board.GetByID(ctx, board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Board.AddContributorToBoard(ctx, member.AsContributor(ContributorMember), fullBoard); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Board.AddContributorToBoard(ctx, admin.AsContributor(ContributorAdmin), fullBoard); err != nil {
+		t.Fatal(err)
+	}
+
+	lonelyBoard, err := db.Board.Create(ctx, Board{Name: "lonely", Owner: BoardOwner{ID: board.Owner.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	abandonedBoard, err := db.Board.Create(ctx, Board{Name: "abandoned", Owner: BoardOwner{ID: board.Owner.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trashedBoard, err := db.Board.Create(ctx, Board{Name: "trashed", Owner: BoardOwner{ID: board.Owner.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Board.DeleteByID(ctx, trashedBoard.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Person.DeleteByID(ctx, board.Owner.ID, nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("Person with boards without successors deleted, expected ErrConflict, got: %v", err)
+	}
+	err = db.Person.DeleteByID(ctx, board.Owner.ID, map[uint32]uint32{board.ID: outsider.ID})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Board transferred to person, who isn't its contributor, expected ErrNotFound, got: %v", err)
+	}
+	err = db.Person.DeleteByID(ctx, board.Owner.ID, map[uint32]uint32{board.ID: board.Owner.ID})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Board transferred to deleted person, expected ErrInvalidInput, got: %v", err)
+	}
+
+	successors := map[uint32]uint32{board.ID: admin.ID, lonelyBoard.ID: outsider.ID, trashedBoard.ID: outsider.ID}
+	if err := db.Person.DeleteByID(ctx, board.Owner.ID, successors); err != nil {
+		t.Fatal(err)
+	}
+
+	var abandonedOwnerID uint32
+	var isTrashed bool
+	if err := db.conn.QueryRow(ctx, "SELECT owner_id, deleted_at IS NOT NULL FROM board WHERE board_id = $1;",
+		abandonedBoard.ID).Scan(&abandonedOwnerID, &isTrashed); err != nil {
+		t.Fatalf("Board without successor was deleted instead of moving to trash: %v", err)
+	}
+	if abandonedOwnerID != 0 || !isTrashed {
+		t.Errorf("Board without successor has owner %d and trashed %v, expected null person in trash",
+			abandonedOwnerID, isTrashed)
+	}
+
+	transferredLonelyBoard, err := db.Board.GetByID(ctx, lonelyBoard.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transferredLonelyBoard.Owner.ID != outsider.ID {
+		t.Errorf("Board without contributors transferred to %v, expected %v", transferredLonelyBoard.Owner, outsider)
+	}
+
+	if _, err := db.Board.Restore(ctx, trashedBoard.ID, outsider.ID); err != nil {
+		t.Errorf("Board in trash can't be restored by its successor: %v", err)
+	}
+
+	transferredBoard, err := db.Board.GetByID(ctx, board.ID)
+	if err != nil {
+		t.Fatalf("Board of deleted person was deleted instead of transferring: %v", err)
+	}
+	if transferredBoard.Owner.ID != admin.ID {
+		t.Errorf("Board transferred to %v, expected admin contributor %v", transferredBoard.Owner, admin)
+	}
+
+	if err := db.Board.DeleteByID(ctx, board.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Board.TransferOwnership(ctx, transferredBoard, member.AsContributor(""))
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("BoardModel.TransferOwnership() returned %v for board in trash, expected ErrConflict", err)
+	}
+}
+
+func TestSystemMigrations(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := db.System.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(version) != len(migrations) {
+		t.Errorf("Schema version after recreating tables is %d, expected %d", version, len(migrations))
+	}
+
+	applied, err := db.System.MigrateUp(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied != 0 {
+		t.Errorf("SystemModel.MigrateUp() applied %d migrations to up-to-date schema", applied)
+	}
+
+	reverted, err := db.System.MigrateDown(ctx, len(migrations)+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverted != len(migrations) {
+		t.Errorf("SystemModel.MigrateDown() reverted %d migrations, expected %d", reverted, len(migrations))
+	}
+
+	isExist, err := db.System.IsTableExist(ctx, "person")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isExist {
+		t.Error("Table 'person' exists after reverting all migrations")
+	}
+
+	applied, err = db.System.MigrateUp(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations) {
+		t.Errorf("SystemModel.MigrateUp() applied %d migrations, expected %d", applied, len(migrations))
+	}
+}
+
+func TestSystemMigrateUpAdopt(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// schema created before migrations were introduced: baseline tables without versions table
+	if _, err := db.System.MigrateDown(ctx, len(migrations)-1); err != nil {
+		t.Fatal(err)
+	}
+	const legacySQL = ("DROP TABLE schema_migrations;" +
+		"INSERT INTO person (username, first_name, last_name, email, password_hash) " +
+		"VALUES ('legacy', 'legacy', 'legacy', 'legacy@mail.com', 'hash');" +
+		"INSERT INTO board (board_name, owner_id) SELECT 'legacy', person_id FROM person WHERE username = 'legacy';" +
+		"INSERT INTO task (task_name, board_id, author_id) " +
+		"SELECT 'legacy', board_id, owner_id FROM board WHERE board_name = 'legacy';")
+	if _, err := db.conn.Exec(ctx, legacySQL); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := db.System.MigrateUp(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations)-1 {
+		t.Errorf("SystemModel.MigrateUp() applied %d migrations to adopted schema, expected %d",
+			applied, len(migrations)-1)
+	}
+
+	version, err := db.System.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(version) != len(migrations) {
+		t.Errorf("Schema version after adopting is %d, expected %d", version, len(migrations))
+	}
+
+	var columnName string
+	if err := db.conn.QueryRow(ctx, "SELECT \"column\".column_name FROM task "+
+		"JOIN \"column\" ON \"column\".column_id = task.column_id WHERE task.task_name = 'legacy';",
+	).Scan(&columnName); err != nil {
+		t.Fatal(err)
+	}
+	if columnName != DefaultColumns[0] {
+		t.Errorf("Task of adopted schema is placed in column %q, expected %q", columnName, DefaultColumns[0])
+	}
+}
+
+func TestSystemMigrateUpConcurrent(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.System.MigrateDown(ctx, len(migrations)); err != nil {
+		t.Fatal(err)
+	}
+
+	const instances = 4
+	results := make(chan int, instances)
+	errs := make(chan error, instances)
+	for i := 0; i < instances; i++ {
+		go func() {
+			applied, err := db.System.MigrateUp(ctx)
+			results <- applied
+			errs <- err
+		}()
+	}
+
+	total := 0
+	for i := 0; i < instances; i++ {
+		total += <-results
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+	if total != len(migrations) {
+		t.Errorf("Concurrent SystemModel.MigrateUp() applied %d migrations in total, expected %d",
+			total, len(migrations))
+	}
+}
+
+func TestDBWithTx(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedBoards(); err != nil {
+		t.Fatal(err)
+	}
+
+	errRollback := errors.New("rollback")
+	mockedTask := mockedData.Tasks[0]
+
+	var rolledBackTaskID uint32
+	err = db.WithTx(ctx, func(tx DB) error {
+		task, err := tx.Task.Create(ctx, mockedTask)
+		if err != nil {
+			return err
+		}
+		rolledBackTaskID = task.ID
+
+		if _, err := tx.Task.AddSubtaskToTask(ctx, Subtask{Name: "subtask"}, task); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("DB.WithTx() returned %v, expected error returned by fn", err)
+	}
+	if _, err := db.Task.GetByID(ctx, rolledBackTaskID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Task created in rolled back transaction exists, GetByID() returned %v", err)
+	}
+
+	var committedTaskID, nestedTaskID uint32
+	err = db.WithTx(ctx, func(tx DB) error {
+		task, err := tx.Task.Create(ctx, mockedTask)
+		if err != nil {
+			return err
+		}
+		committedTaskID = task.ID
+
+		nestedErr := tx.WithTx(ctx, func(nestedTx DB) error {
+			nestedTask, err := nestedTx.Task.Create(ctx, mockedTask)
+			if err != nil {
+				return err
+			}
+			nestedTaskID = nestedTask.ID
+			return errRollback
+		})
+		if !errors.Is(nestedErr, errRollback) {
+			return fmt.Errorf("nested DB.WithTx() returned %v, expected error returned by fn", nestedErr)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Task.GetByID(ctx, committedTaskID); err != nil {
+		t.Errorf("Task created in committed transaction not found: %v", err)
+	}
+	if _, err := db.Task.GetByID(ctx, nestedTaskID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Task created in rolled back savepoint exists, GetByID() returned %v", err)
+	}
+}
+
+// countingConn - DBConn that counts queries sent through it.
+type countingConn struct {
+	DBConn
+	queries int64
+}
+
+func (c *countingConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	atomic.AddInt64(&c.queries, 1)
+	return c.DBConn.Query(ctx, sql, args...)
+}
+
+func (c *countingConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	atomic.AddInt64(&c.queries, 1)
+	return c.DBConn.QueryRow(ctx, sql, args...)
+}
+
+func (c *countingConn) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	atomic.AddInt64(&c.queries, 1)
+	return c.DBConn.Exec(ctx, sql, args...)
+}
+
+// createBenchmarkBoard - creates board for benchmarks with tasksCount tasks, each task has tag, subtask and
+// is assigned to board owner. Returns created board.
+func createBenchmarkBoard(b *testing.B, tasksCount int) Board {
+	b.Helper()
+	ctx := context.Background()
+
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		b.Fatal(err)
+	}
+	mockedData, err := LoadMockData()
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		b.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		b.Fatal(err)
+	}
+	tag, err := db.Tag.Create(ctx, Tag{Name: "tag", BoardID: board.ID})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for i := 0; i < tasksCount; i++ {
+		task, err := db.Task.Create(ctx, Task{Name: fmt.Sprintf("task %d", i),
+			Author: TaskAuthor(board.Owner), BoardID: board.ID})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := db.Task.AddTagToTask(ctx, tag, task); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := db.Task.AddAssigneeToTask(ctx, TaskAssignee(board.Owner), task); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := db.Task.AddSubtaskToTask(ctx, Subtask{Name: "subtask"}, task); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return board
+}
+
+func BenchmarkBoardGetByID(b *testing.B) {
+	ctx := context.Background()
+	for _, tasksCount := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("tasks=%d", tasksCount), func(b *testing.B) {
+			board := createBenchmarkBoard(b, tasksCount)
+			conn := &countingConn{DBConn: db.conn}
+			countingDB := NewDB(conn)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := countingDB.Board.GetByID(ctx, board.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(atomic.LoadInt64(&conn.queries))/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkPersonGetByID(b *testing.B) {
+	ctx := context.Background()
+	for _, tasksCount := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("tasks=%d", tasksCount), func(b *testing.B) {
+			board := createBenchmarkBoard(b, tasksCount)
+			conn := &countingConn{DBConn: db.conn}
+			countingDB := NewDB(conn)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := countingDB.Person.GetByID(ctx, board.Owner.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(atomic.LoadInt64(&conn.queries))/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestBoardGetByIDQueryCount(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := db.Tag.Create(ctx, Tag{Name: "tag", BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queriesByTasks := map[int]int64{}
+	for tasksCount := 1; tasksCount <= 50; tasksCount++ {
+		task, err := db.Task.Create(ctx, Task{Name: fmt.Sprintf("task %d", tasksCount),
+			Author: TaskAuthor(board.Owner), BoardID: board.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Task.AddTagToTask(ctx, tag, task); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Task.AddAssigneeToTask(ctx, TaskAssignee(board.Owner), task); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Task.AddSubtaskToTask(ctx, Subtask{Name: "subtask"}, task); err != nil {
+			t.Fatal(err)
+		}
+		if tasksCount != 1 && tasksCount != 50 {
+			continue
+		}
+
+		conn := &countingConn{DBConn: db.conn}
+
+		loadedBoard, err := NewDB(conn).Board.GetByID(ctx, board.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loadedBoard.Tasks) != tasksCount {
+			t.Fatalf("Loaded %d tasks, expected %d", len(loadedBoard.Tasks), tasksCount)
+		}
+		queriesByTasks[tasksCount] = conn.queries
+	}
+
+	if queriesByTasks[1] != queriesByTasks[50] {
+		t.Errorf("BoardModel.GetByID() query count depends on tasks count: %v", queriesByTasks)
+	}
+}
+
+func TestBoardGetByIDLoadOptions(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := db.Tag.Create(ctx, Tag{Name: "tag", BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"first", "second"} {
+		task, err := db.Task.Create(ctx, Task{Name: name, Author: TaskAuthor(board.Owner), BoardID: board.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Task.AddTagToTask(ctx, tag, task); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Task.AddAssigneeToTask(ctx, TaskAssignee(board.Owner), task); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Task.AddSubtaskToTask(ctx, Subtask{Name: "subtask"}, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conn := &countingConn{DBConn: db.conn}
+	smallBoard, err := NewDB(conn).Board.GetSmallByID(ctx, board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(board.Small(), smallBoard); diff != "" {
+		t.Errorf("BoardModel.GetSmallByID() mismatch (-want +got):\n%s", diff)
+	}
+	if conn.queries != 1 {
+		t.Errorf("BoardModel.GetSmallByID() made %d queries, expected 1", conn.queries)
+	}
+
+	loadedBoard, err := db.Board.GetByID(ctx, board.ID, WithTasks(), WithTags())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loadedBoard.Tasks) != 2 || len(loadedBoard.Tags) != 1 {
+		t.Fatalf("Loaded %d tasks and %d tags, expected 2 and 1", len(loadedBoard.Tasks), len(loadedBoard.Tags))
+	}
+	if loadedBoard.Columns != nil || loadedBoard.Contributors != nil {
+		t.Errorf("Columns and contributors are loaded without options")
+	}
+	for _, task := range loadedBoard.Tasks {
+		if len(task.Tags) != 1 || task.Subtasks != nil || task.Assignees != nil {
+			t.Errorf("Task %d loaded with wrong relations: %+v", task.ID, task)
+		}
+	}
+
+	person, err := db.Person.GetByID(ctx, board.Owner.ID, WithBoards())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(person.Boards) != 1 || person.AssignedTasks != nil {
+		t.Errorf("PersonModel.GetByID(WithBoards()) loaded %d boards and %d assigned tasks, expected 1 and 0",
+			len(person.Boards), len(person.AssignedTasks))
+	}
+
+	smallPerson, err := db.Person.GetSmallByID(ctx, board.Owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(SmallPerson(board.Owner), smallPerson); diff != "" {
+		t.Errorf("PersonModel.GetSmallByID() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Person.GetByID(ctx, 1000); !errors.Is(err, ErrNotFound) {
+		t.Errorf("PersonModel.GetByID() returned %v for missing person, expected ErrNotFound", err)
+	}
+
+	_, err = db.Person.Create(ctx, mockedData.Persons[0])
+	var dbErr *Error
+	if !errors.Is(err, ErrConflict) || !errors.As(err, &dbErr) || dbErr.Constraint != "person_username_key" {
+		t.Errorf("PersonModel.Create() returned %v for duplicate person, expected ErrConflict", err)
+	}
+
+	_, err = db.Board.Create(ctx, Board{Name: "board", Owner: BoardOwner{ID: 1000}})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("BoardModel.Create() returned %v for missing owner, expected ErrInvalidInput", err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := Contributor{ID: board.Owner.ID}
+	if _, err := db.Board.AddContributorToBoard(ctx, owner, board); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("BoardModel.AddContributorToBoard() returned %v for board owner, expected ErrInvalidInput", err)
+	}
+}
+
+// failingQueryConn - DBConn, whose Query always fails, like on broken connection.
+type failingQueryConn struct {
+	DBConn
+}
+
+var errQueryFailed = errors.New("query failed")
+
+func (c failingQueryConn) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errQueryFailed
+}
+
+func TestLoadQueryErrorsPropagated(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := db.Task.Create(ctx, Task{Name: "task", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failingDB := NewDB(failingQueryConn{DBConn: db.conn})
+
+	if _, err := failingDB.Board.GetByID(ctx, board.ID); !errors.Is(err, errQueryFailed) {
+		t.Errorf("BoardModel.GetByID() returned %v on failed query, expected errQueryFailed", err)
+	}
+
+	if _, err := failingDB.Person.GetByID(ctx, board.Owner.ID); !errors.Is(err, errQueryFailed) {
+		t.Errorf("PersonModel.GetByID() returned %v on failed query, expected errQueryFailed", err)
+	}
+
+	if _, err := failingDB.Task.GetByID(ctx, task.ID); !errors.Is(err, errQueryFailed) {
+		t.Errorf("TaskModel.GetByID() returned %v on failed query, expected errQueryFailed", err)
+	}
+}
+
+func TestTaskDueDates(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Truncate(time.Microsecond)
+	yesterday, tomorrow, nextWeek := now.Add(-24*time.Hour), now.Add(24*time.Hour), now.Add(7*24*time.Hour)
+
+	overdueTask, err := db.Task.Create(ctx, Task{Name: "overdue", Author: TaskAuthor(board.Owner),
+		BoardID: board.ID, StartAt: &yesterday, DueAt: &yesterday})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overdueTask.DueAt == nil || !overdueTask.DueAt.Equal(yesterday) {
+		t.Errorf("Created task due date is %v, expected %v", overdueTask.DueAt, yesterday)
+	}
+
+	dueTask, err := db.Task.Create(ctx, Task{Name: "due", Author: TaskAuthor(board.Owner),
+		BoardID: board.ID, DueAt: &nextWeek})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Task.Create(ctx, Task{Name: "undated", Author: TaskAuthor(board.Owner),
+		BoardID: board.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Task.Create(ctx, Task{Name: "invalid", Author: TaskAuthor(board.Owner),
+		BoardID: board.ID, StartAt: &tomorrow, DueAt: &yesterday}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("TaskModel.Create() returned %v for start after due, expected ErrInvalidInput", err)
+	}
+
+	dueTasks, err := db.Task.GetDueBetween(ctx, board.ID, now, nextWeek.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dueTasks) != 1 || dueTasks[0].ID != dueTask.ID {
+		t.Errorf("TaskModel.GetDueBetween() returned %v, expected only task %d", dueTasks, dueTask.ID)
+	}
+
+	if _, err := db.Board.Archive(ctx, board.ID); err != nil {
+		t.Fatal(err)
+	}
+	dueTasks, err = db.Task.GetDueBetween(ctx, board.ID, now, nextWeek.Add(time.Hour))
+	if err != nil || len(dueTasks) != 0 {
+		t.Errorf("TaskModel.GetDueBetween() returned %v, %v for archived board, expected no tasks", dueTasks, err)
+	}
+	if _, err := db.Board.Unarchive(ctx, board.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, task := range []Task{overdueTask, dueTask} {
+		if _, err := db.Task.AddAssigneeToTask(ctx, TaskAssignee(board.Owner), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	overdueTasks, err := db.Task.GetOverdueByAssignee(ctx, board.Owner.ID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overdueTasks) != 1 || overdueTasks[0].ID != overdueTask.ID {
+		t.Errorf("TaskModel.GetOverdueByAssignee() returned %v, expected only task %d", overdueTasks, overdueTask.ID)
+	}
+
+	updatedTask, err := db.Task.Update(ctx, overdueTask.ID, TaskUpdate{ClearStartAt: true, DueAt: &tomorrow})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedTask.StartAt != nil || updatedTask.DueAt == nil || !updatedTask.DueAt.Equal(tomorrow) {
+		t.Errorf("Updated task dates are %v - %v, expected <nil> - %v",
+			updatedTask.StartAt, updatedTask.DueAt, tomorrow)
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.CreatedAt.IsZero() || !board.UpdatedAt.Equal(board.CreatedAt) {
+		t.Errorf("Created board timestamps are %v - %v, expected equal non-zero", board.CreatedAt, board.UpdatedAt)
+	}
+
+	task, err := db.Task.Create(ctx, Task{Name: "task", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.CreatedAt.IsZero() || !task.UpdatedAt.Equal(task.CreatedAt) || task.LastModifiedBy != nil {
+		t.Errorf("Created task timestamps are %v - %v, modified by %v, expected equal non-zero and <nil>",
+			task.CreatedAt, task.UpdatedAt, task.LastModifiedBy)
+	}
+
+	unchangedTask, err := db.Task.Update(ctx, task.ID, TaskUpdate{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !unchangedTask.UpdatedAt.Equal(task.UpdatedAt) {
+		t.Errorf("Empty update changed updated_at of task from %v to %v", task.UpdatedAt, unchangedTask.UpdatedAt)
+	}
+
+	actorCtx := WithActor(ctx, board.Owner.ID)
+	name := "renamed"
+	updatedTask, err := db.Task.Update(actorCtx, task.ID, TaskUpdate{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updatedTask.UpdatedAt.After(task.UpdatedAt) || !updatedTask.CreatedAt.Equal(task.CreatedAt) {
+		t.Errorf("Updated task timestamps are %v - %v, expected same created_at and updated_at after %v",
+			updatedTask.CreatedAt, updatedTask.UpdatedAt, task.UpdatedAt)
+	}
+	if updatedTask.LastModifiedBy == nil || *updatedTask.LastModifiedBy != board.Owner.ID {
+		t.Errorf("Updated task modified by %v, expected %d", updatedTask.LastModifiedBy, board.Owner.ID)
+	}
+
+	taskWithSubtask, err := db.Task.AddSubtaskToTask(actorCtx, Subtask{Name: "subtask"}, updatedTask)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !taskWithSubtask.UpdatedAt.After(updatedTask.UpdatedAt) {
+		t.Errorf("Adding subtask didn't change updated_at of task %v", updatedTask.UpdatedAt)
+	}
+	if len(taskWithSubtask.Subtasks) != 1 || taskWithSubtask.Subtasks[0].CreatedAt.IsZero() {
+		t.Errorf("Task subtasks are %v, expected one subtask with created_at", taskWithSubtask.Subtasks)
+	}
+
+	updatedBoard, err := db.Board.Update(ctx, board.ID, BoardUpdate{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updatedBoard.UpdatedAt.After(board.UpdatedAt) || !updatedBoard.CreatedAt.Equal(board.CreatedAt) {
+		t.Errorf("Updated board timestamps are %v - %v, expected same created_at and updated_at after %v",
+			updatedBoard.CreatedAt, updatedBoard.UpdatedAt, board.UpdatedAt)
+	}
+}
+
+func TestTaskPriorityAndEstimate(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	board, err = db.Board.GetByID(ctx, board.ID, WithColumns())
+	if err != nil {
+		t.Fatal(err)
+	}
+	todo, done := board.Columns[0], board.Columns[len(board.Columns)-1]
+
+	estimate := func(e float64) *float64 { return &e }
+	created := map[string]Task{}
+	for _, task := range []Task{
+		{Name: "low", Priority: PriorityLow, Estimate: estimate(5), ColumnID: todo.ID},
+		{Name: "urgent", Priority: PriorityUrgent, Estimate: estimate(1), ColumnID: todo.ID},
+		{Name: "unset", ColumnID: todo.ID},
+		{Name: "high", Priority: PriorityHigh, Estimate: estimate(2.5), ColumnID: done.ID},
+	} {
+		task.Author, task.BoardID = TaskAuthor(board.Owner), board.ID
+		createdTask, err := db.Task.Create(ctx, task)
+		if err != nil {
+			t.Fatal(err)
+		}
+		created[task.Name] = createdTask
+	}
+
+	if created["unset"].Priority != PriorityNone || created["unset"].Estimate != nil {
+		t.Errorf("Task created without priority and estimate has %q and %v, expected %q and <nil>",
+			created["unset"].Priority, created["unset"].Estimate, PriorityNone)
+	}
+
+	if _, err := db.Task.Create(ctx, Task{Name: "invalid", Priority: "whenever", Author: TaskAuthor(board.Owner),
+		BoardID: board.ID}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("TaskModel.Create() returned %v for unknown priority, expected ErrInvalidInput", err)
+	}
+
+	taskNames := func(tasks []Task) []string {
+		names := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+		return names
+	}
+
+	cases := []struct {
+		expected []string
+		filter   TaskFilter
+	}{
+		{[]string{"low", "urgent", "unset", "high"}, TaskFilter{}},
+		{[]string{"urgent", "high", "low", "unset"}, TaskFilter{Sort: SortByPriority}},
+		{[]string{"urgent", "high", "low", "unset"}, TaskFilter{Sort: SortByEstimate}},
+		{[]string{"urgent", "low", "unset"}, TaskFilter{ColumnID: todo.ID, Sort: SortByPriority}},
+		{[]string{"low", "high"}, TaskFilter{Priorities: []TaskPriority{PriorityLow, PriorityHigh}}},
+		{[]string{"low", "high"}, TaskFilter{MinEstimate: estimate(2), MaxEstimate: estimate(5)}},
+	}
+	for _, c := range cases {
+		c.filter.BoardID = board.ID
+		tasks, err := db.Task.GetByFilter(ctx, c.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if names := taskNames(tasks); !cmp.Equal(names, c.expected) {
+			t.Errorf("TaskModel.GetByFilter(%+v) returned %v, expected %v", c.filter, names, c.expected)
+		}
+	}
+
+	_, err = db.Task.GetByFilter(ctx, TaskFilter{BoardID: board.ID, Sort: "name"})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("TaskModel.GetByFilter() returned %v for unknown sort, expected ErrInvalidInput", err)
+	}
+
+	priority := PriorityMedium
+	updatedTask, err := db.Task.Update(ctx, created["low"].ID, TaskUpdate{Priority: &priority, ClearEstimate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedTask.Priority != PriorityMedium || updatedTask.Estimate != nil {
+		t.Errorf("Updated task has %q and %v, expected %q and <nil>",
+			updatedTask.Priority, updatedTask.Estimate, PriorityMedium)
+	}
+
+	obtainedBoard, err := db.Board.GetByID(ctx, board.ID, WithColumns())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTotals := map[uint32]float64{todo.ID: 1, done.ID: 2.5}
+	for _, column := range obtainedBoard.Columns {
+		if column.EstimateTotal != expectedTotals[column.ID] {
+			t.Errorf("Column %d has estimate total %v, expected %v",
+				column.ID, column.EstimateTotal, expectedTotals[column.ID])
+		}
+	}
+}
+
+func TestTaskSubtaskChecklist(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := db.Task.Create(ctx, Task{Name: "task", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherTask, err := db.Task.Create(ctx, Task{Name: "other", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"first", "second", "third"} {
+		if task, err = db.Task.AddSubtaskToTask(ctx, Subtask{Name: name}, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	subtaskNames := func(task Task) []string {
+		names := make([]string, 0, len(task.Subtasks))
+		for _, subtask := range task.Subtasks {
+			names = append(names, subtask.Name)
+		}
+		return names
+	}
+
+	if names := subtaskNames(task); !cmp.Equal(names, []string{"first", "second", "third"}) {
+		t.Errorf("Subtasks are %v, expected in order of adding", names)
+	}
+	if task.Progress != (TaskProgress{Done: 0, Total: 3}) {
+		t.Errorf("Task progress is %+v, expected 0/3", task.Progress)
+	}
+
+	first, third := task.Subtasks[0], task.Subtasks[2]
+	isDone, name, assigneeID := true, "renamed", board.Owner.ID
+	task, err = db.Task.UpdateSubtask(ctx, third, task,
+		SubtaskUpdate{Name: &name, IsDone: &isDone, AssigneeID: &assigneeID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := task.Subtasks[2]
+	if updated.Name != name || !updated.IsDone || updated.AssigneeID == nil || *updated.AssigneeID != assigneeID {
+		t.Errorf("Updated subtask is %+v, expected renamed, done and assigned to %d", updated, assigneeID)
+	}
+	if task.Progress != (TaskProgress{Done: 1, Total: 3}) {
+		t.Errorf("Task progress is %+v, expected 1/3", task.Progress)
+	}
+
+	obtainedTask, err := db.Task.GetByID(ctx, task.ID, WithoutRelations())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obtainedTask.Progress != task.Progress {
+		t.Errorf("Task obtained without subtasks has progress %+v, expected %+v", obtainedTask.Progress, task.Progress)
+	}
+
+	task, err = db.Task.MoveSubtask(ctx, third, task, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := subtaskNames(task); !cmp.Equal(names, []string{"renamed", "first", "second"}) {
+		t.Errorf("Subtasks after move are %v, expected [renamed first second]", names)
+	}
+
+	task, err = db.Task.MoveSubtask(ctx, third, task, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := subtaskNames(task); !cmp.Equal(names, []string{"first", "second", "renamed"}) {
+		t.Errorf("Subtasks after move to the end are %v, expected [first second renamed]", names)
+	}
+
+	_, err = db.Task.UpdateSubtask(ctx, first, otherTask, SubtaskUpdate{IsDone: &isDone})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("TaskModel.UpdateSubtask() returned %v for subtask of other task, expected ErrNotFound", err)
+	}
+	if _, err := db.Task.MoveSubtask(ctx, first, otherTask, 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TaskModel.MoveSubtask() returned %v for subtask of other task, expected ErrNotFound", err)
+	}
+	if _, err := db.Task.RemoveSubtaskFromTask(ctx, first, otherTask); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TaskModel.RemoveSubtaskFromTask() returned %v for subtask of other task, expected ErrNotFound", err)
+	}
+
+	task, err = db.Task.RemoveSubtaskFromTask(ctx, first, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Progress != (TaskProgress{Done: 1, Total: 2}) {
+		t.Errorf("Task progress after removing subtask is %+v, expected 1/2", task.Progress)
+	}
+}
+
+func TestCommentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := db.Task.Create(ctx, Task{Name: "task", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherTask, err := db.Task.Create(ctx, Task{Name: "other", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comment, err := db.Comment.Create(ctx, Comment{Text: "first", TaskID: task.ID, Author: CommentAuthor(board.Owner)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment.Author != CommentAuthor(board.Owner) || comment.CreatedAt.IsZero() || comment.ReplyToID != nil {
+		t.Errorf("Created comment is %+v, expected authored by %v without reply", comment, board.Owner)
+	}
+
+	reply, err := db.Comment.Create(ctx, Comment{Text: "reply", TaskID: task.ID, ReplyToID: &comment.ID,
+		Author: CommentAuthor(board.Owner)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.ReplyToID == nil || *reply.ReplyToID != comment.ID {
+		t.Errorf("Reply replies to %v, expected %d", reply.ReplyToID, comment.ID)
+	}
+
+	_, err = db.Comment.Create(ctx, Comment{Text: "reply", TaskID: otherTask.ID, ReplyToID: &comment.ID,
+		Author: CommentAuthor(board.Owner)})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("CommentModel.Create() returned %v for reply to comment of other task, expected ErrInvalidInput", err)
+	}
+
+	text := "edited"
+	updatedComment, err := db.Comment.Update(ctx, comment.ID, CommentUpdate{Text: &text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedComment.Text != text || !updatedComment.UpdatedAt.After(comment.UpdatedAt) {
+		t.Errorf("Updated comment is %+v, expected text %q and updated_at after %v",
+			updatedComment, text, comment.UpdatedAt)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := db.Comment.Create(ctx, Comment{Text: fmt.Sprint(i), TaskID: task.ID,
+			Author: CommentAuthor(board.Owner)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var texts []string
+	page := Page{Limit: 2}
+	for {
+		comments, err := db.Comment.GetByTask(ctx, task.ID, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(comments) == 0 {
+			break
+		}
+		for _, c := range comments {
+			texts = append(texts, c.Text)
+		}
+		page.AfterID = comments[len(comments)-1].ID
+	}
+	if expected := []string{"edited", "reply", "0", "1", "2"}; !cmp.Equal(texts, expected) {
+		t.Errorf("Paginated comments are %v, expected %v", texts, expected)
+	}
+
+	if err := db.Comment.DeleteByID(ctx, comment.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Comment.GetByID(ctx, reply.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CommentModel.GetByID() returned %v for reply to deleted comment, expected ErrNotFound", err)
+	}
+}
+
+func TestActivityLog(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Tag.Create(ctx, Tag{Name: "tag", BoardID: board.ID}); err != nil {
+		t.Fatal(err)
+	}
+	board, err = db.Board.GetByID(ctx, board.ID, WithColumns(), WithTags())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = WithActor(ctx, board.Owner.ID)
+
+	task, err := db.Task.Create(ctx, Task{Name: "task", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := "renamed"
+	if _, err := db.Task.Update(ctx, task.ID, TaskUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	if task, err = db.Task.AddTagToTask(ctx, board.Tags[0], task); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.Move(ctx, task, board.Columns[1], 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Board.RemoveTagFromBoard(ctx, board.Tags[0], board); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Task.DeleteByID(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	var boardActivity []string
+	page := Page{Limit: 3}
+	for {
+		activity, err := db.Activity.GetByBoard(ctx, board.ID, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(activity) == 0 {
+			break
+		}
+		for _, a := range activity {
+			boardActivity = append(boardActivity, fmt.Sprintf("%s %s", a.EntityType, a.Verb))
+		}
+		page.AfterID = activity[len(activity)-1].ID
+	}
+	expectedBoardActivity := []string{"task update", "tag delete", "task move", "task_tag create", "task update",
+		"task create", "tag create", "column create", "column create", "column create", "board create"}
+	if !cmp.Equal(boardActivity, expectedBoardActivity) {
+		t.Errorf("Board activity is %v, expected %v", boardActivity, expectedBoardActivity)
+	}
+
+	activity, err := db.Activity.GetByTask(ctx, board.ID, task.ID, Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 5 {
+		t.Fatalf("Task activity has %d records, expected 5", len(activity))
+	}
+	for _, a := range activity {
+		if a.TaskID == nil || *a.TaskID != task.ID || a.ActorID == nil || *a.ActorID != board.Owner.ID {
+			t.Errorf("Task activity %+v, expected task %d and actor %d", a, task.ID, board.Owner.ID)
+		}
+	}
+
+	deleted, moved, created := activity[0], activity[1], activity[4]
+	if created.Before != nil {
+		t.Errorf("Created task has before %s, expected null", created.Before)
+	}
+
+	var trashed struct {
+		DeletedAt *time.Time `json:"deleted_at"`
+	}
+	if err := json.Unmarshal(deleted.After, &trashed); err != nil {
+		t.Fatal(err)
+	}
+	if trashed.DeletedAt == nil {
+		t.Errorf("Task moved to trash has after %s, expected deleted_at to be set", deleted.After)
+	}
+
+	var before, after struct {
+		ColumnID uint32 `json:"column_id"`
+	}
+	if err := json.Unmarshal(moved.Before, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(moved.After, &after); err != nil {
+		t.Fatal(err)
+	}
+	if before.ColumnID != board.Columns[0].ID || after.ColumnID != board.Columns[1].ID {
+		t.Errorf("Task moved from column %d to %d, expected from %d to %d",
+			before.ColumnID, after.ColumnID, board.Columns[0].ID, board.Columns[1].ID)
+	}
+
+	if _, err := db.conn.Exec(ctx, "DELETE FROM activity;"); err == nil {
+		t.Error("Activity deleted, expected it to be append-only")
+	}
+}
+
+func TestTrash(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := db.Tag.Create(ctx, Tag{Name: "tag", BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := db.Task.Create(ctx, Task{Name: "task", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.AddTagToTask(ctx, tag, task); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.AddAssigneeToTask(ctx, TaskAssignee(board.Owner), task); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.AddSubtaskToTask(ctx, Subtask{Name: "subtask"}, task); err != nil {
+		t.Fatal(err)
+	}
+	otherTask, err := db.Task.Create(ctx, Task{Name: "other", Author: TaskAuthor(board.Owner), BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Task.DeleteByID(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.GetByID(ctx, task.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TaskModel.GetByID() returned %v for task in trash, expected ErrNotFound", err)
+	}
+	deletedTasks, err := db.Task.GetDeleted(ctx, board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deletedTasks) != 1 || deletedTasks[0].ID != task.ID || deletedTasks[0].DeletedAt == nil {
+		t.Errorf("Tasks in trash are %+v, expected only task %d", deletedTasks, task.ID)
+	}
+
+	if _, err := db.Task.Restore(ctx, board.ID+1, task.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TaskModel.Restore() returned %v for task of other board, expected ErrNotFound", err)
+	}
+	restoredTask, err := db.Task.Restore(ctx, board.ID, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restoredTask.DeletedAt != nil || restoredTask.Position <= otherTask.Position {
+		t.Errorf("Restored task is %+v, expected it out of trash after task on position %d",
+			restoredTask, otherTask.Position)
+	}
+	if len(restoredTask.Tags) != 1 || len(restoredTask.Assignees) != 1 || len(restoredTask.Subtasks) != 1 {
+		t.Errorf("Restored task lost its relations: %+v", restoredTask)
+	}
+	if _, err := db.Task.Restore(ctx, board.ID, task.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TaskModel.Restore() returned %v for task not in trash, expected ErrNotFound", err)
+	}
+
+	if err := db.Board.DeleteByID(ctx, board.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Board.GetByID(ctx, board.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("BoardModel.GetByID() returned %v for board in trash, expected ErrNotFound", err)
+	}
+	owner, err := db.Person.GetByID(ctx, board.Owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owner.Boards) != 0 || len(owner.AssignedTasks) != 0 {
+		t.Errorf("Owner has boards %v and assigned tasks %v of board in trash", owner.Boards, owner.AssignedTasks)
+	}
+
+	if _, err := db.Board.Restore(ctx, board.ID, board.Owner.ID+1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("BoardModel.Restore() returned %v for board of other person, expected ErrNotFound", err)
+	}
+	restoredBoard, err := db.Board.Restore(ctx, board.ID, board.Owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restoredBoard.Tasks) != 2 || len(restoredBoard.Tags) != 1 {
+		t.Errorf("Restored board is %+v, expected it with 2 tasks and tag", restoredBoard)
+	}
+
+	if err := db.Task.DeleteByID(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Board.DeleteByID(ctx, board.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, purge := range []func(context.Context, time.Time) (int64, error){
+		db.Task.PurgeDeleted, db.Board.PurgeDeleted} {
+		if purged, err := purge(ctx, time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+			t.Errorf("PurgeDeleted() purged %d rows deleted after deletedBefore, error: %v", purged, err)
+		}
+		if purged, err := purge(ctx, time.Now().Add(time.Minute)); err != nil || purged != 1 {
+			t.Errorf("PurgeDeleted() purged %d rows, expected 1, error: %v", purged, err)
+		}
+	}
+
+	if _, err := db.Board.Restore(ctx, board.ID, board.Owner.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("BoardModel.Restore() returned %v for purged board, expected ErrNotFound", err)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := db.Tag.Create(ctx, Tag{Name: "tag", BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		_, err := db.Task.Create(ctx, Task{Name: fmt.Sprintf("task %d", i), Author: TaskAuthor(board.Owner),
+			BoardID: board.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	board, err = db.Board.GetByID(ctx, board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, column := board.Tasks[0], board.Columns[0]
+	if _, err := db.Task.AddTagToTask(ctx, tag, task); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.AddSubtaskToTask(ctx, Subtask{Name: "subtask"}, task); err != nil {
+		t.Fatal(err)
+	}
+
+	archivedTask, err := db.Task.Archive(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archivedTask.ArchivedAt == nil || len(archivedTask.Tags) != 1 || len(archivedTask.Subtasks) != 1 {
+		t.Errorf("Archived task is %+v, expected it archived with its relations", archivedTask)
+	}
+
+	activeBoard, err := db.Board.GetByID(ctx, board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activeBoard.Tasks) != 2 || len(activeBoard.Columns[0].Tasks) != 2 {
+		t.Errorf("Board has tasks %v, expected archived task to be hidden", activeBoard.Tasks)
+	}
+	fullBoard, err := db.Board.GetByID(ctx, board.ID, WithArchived())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fullBoard.Tasks) != 3 || fullBoard.Columns[0].Tasks[2].ID != task.ID {
+		t.Errorf("Board loaded WithArchived has tasks %v, expected archived task after active ones", fullBoard.Tasks)
+	}
+
+	filtered, err := db.Task.GetByFilter(ctx, TaskFilter{BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	withArchived, err := db.Task.GetByFilter(ctx, TaskFilter{BoardID: board.ID, IncludeArchived: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 2 || len(withArchived) != 3 {
+		t.Errorf("GetByFilter() returned %d and %d tasks, expected 2 without and 3 with archived",
+			len(filtered), len(withArchived))
+	}
+
+	unarchivedTask, err := db.Task.Unarchive(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unarchivedTask.ArchivedAt != nil || unarchivedTask.Position <= board.Tasks[2].Position {
+		t.Errorf("Unarchived task is %+v, expected it active at the end of column", unarchivedTask)
+	}
+
+	archived, err := db.Column.ArchiveTasks(ctx, column.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emptyColumn, err := db.Column.GetByID(ctx, column.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archived != 3 || len(emptyColumn.Tasks) != 0 || emptyColumn.EstimateTotal != 0 {
+		t.Errorf("ArchiveTasks() archived %d tasks, column is %+v, expected 3 tasks archived", archived, emptyColumn)
+	}
+
+	if _, err := db.Board.Archive(ctx, board.ID); err != nil {
+		t.Fatal(err)
+	}
+	owner, err := db.Person.GetByID(ctx, board.Owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owner.Boards) != 0 || len(owner.ArchivedBoards) != 1 || owner.ArchivedBoards[0].ID != board.ID {
+		t.Errorf("Owner has boards %v and archived boards %v, expected only archived board %d",
+			owner.Boards, owner.ArchivedBoards, board.ID)
+	}
+
+	unarchivedBoard, err := db.Board.Unarchive(ctx, board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err = db.Person.GetByID(ctx, board.Owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unarchivedBoard.ArchivedAt != nil || len(owner.Boards) != 1 || len(owner.ArchivedBoards) != 0 {
+		t.Errorf("Owner has boards %v and archived boards %v after unarchiving board %d",
+			owner.Boards, owner.ArchivedBoards, board.ID)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := db.Tag.Create(ctx, Tag{Name: "tag", BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := make([]Task, 3)
+	for i := range tasks {
+		tasks[i], err = db.Task.Create(ctx, Task{Name: fmt.Sprintf("task %d", i), Author: TaskAuthor(board.Owner),
+			BoardID: board.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Task.AddTagToTask(ctx, tag, tasks[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	description := "Deploy the payment service"
+	if _, err := db.Task.Update(ctx, tasks[0].ID, TaskUpdate{Description: &description}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Comment.Create(ctx, Comment{Text: "Payments are broken", TaskID: tasks[1].ID,
+		Author: CommentAuthor(board.Owner)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.AddSubtaskToTask(ctx, Subtask{Name: "Payment form"}, tasks[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := db.Search.Search(ctx, SearchQuery{Text: "payment", PersonID: board.Owner.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOrder := []uint32{tasks[0].ID, tasks[2].ID, tasks[1].ID}
+	if len(results) != len(expectedOrder) {
+		t.Fatalf("Search() returned %d results, expected %d", len(results), len(expectedOrder))
+	}
+	for i, result := range results {
+		if result.Task.ID != expectedOrder[i] {
+			t.Errorf("Result %d is task %d, expected task %d ranked by description, subtask and comment",
+				i, result.Task.ID, expectedOrder[i])
+		}
+	}
+	if !strings.Contains(results[0].Highlights.Description, "<b>payment</b>") || len(results[0].Task.Tags) != 1 {
+		t.Errorf("First result is %+v, expected highlighted description and loaded task", results[0])
+	}
+	if len(results[1].Highlights.Subtasks) != 1 || len(results[2].Highlights.Comments) != 1 {
+		t.Errorf("Results have highlights %+v and %+v, expected matched subtask and comment",
+			results[1].Highlights, results[2].Highlights)
+	}
+
+	if _, err := db.Task.Archive(ctx, tasks[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]struct {
+		query    SearchQuery
+		expected int
+	}{
+		"archived excluded": {SearchQuery{Text: "payment", PersonID: board.Owner.ID}, 2},
+		"archived included": {SearchQuery{Text: "payment", PersonID: board.Owner.ID, IncludeArchived: true}, 3},
+		"phrase":            {SearchQuery{Text: `"payment service"`, PersonID: board.Owner.ID}, 1},
+		"excluded word":     {SearchQuery{Text: "payment -form", PersonID: board.Owner.ID}, 1},
+		"author":            {SearchQuery{Text: "payment", PersonID: board.Owner.ID, AuthorID: board.Owner.ID + 1}, 0},
+		"outsider":          {SearchQuery{Text: "payment", PersonID: board.Owner.ID + 1}, 0},
+	}
+	for name, c := range cases {
+		results, err := db.Search.Search(ctx, c.query)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if len(results) != c.expected {
+			t.Errorf("%s: Search() returned %d results, expected %d", name, len(results), c.expected)
+		}
+	}
+
+	// tasks created before migrations may have no description
+	if _, err := db.conn.Exec(ctx, "UPDATE task SET task_description = NULL WHERE task_id = $1;",
+		tasks[2].ID); err != nil {
+		t.Fatal(err)
+	}
+	results, err = db.Search.Search(ctx, SearchQuery{Text: "payment form", PersonID: board.Owner.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Task.ID != tasks[2].ID || results[0].Highlights.Description != "" {
+		t.Errorf("Search() returned %+v, expected task without description", results)
+	}
+
+	_, err = db.Search.Search(ctx, SearchQuery{Text: " ", PersonID: board.Owner.ID})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Search() returned %v for empty text, expected ErrInvalidInput", err)
+	}
+}
+
+func TestParseTaskQuery(t *testing.T) {
+	valid := map[string]string{
+		"":                        "TRUE",
+		"deploy":                  "(strpos(lower(task.task_name), lower($2::VARCHAR)) > 0 OR ",
+		"-tag:blocked":            ") IS NOT TRUE",
+		`tag:"needs work"`:        "lower(tag.tag_name) = lower($2::VARCHAR)",
+		"priority:>=high":         "array_position($2::VARCHAR[], task.task_priority) >= $3",
+		"estimate:none":           "task.estimate IS NULL",
+		"due:<7d":                 "task.due_at < now() + $2::INTERVAL",
+		"start:2024-01-02":        "(task.start_at >= $2::TIMESTAMPTZ AND task.start_at < $3::TIMESTAMPTZ)",
+		"assignee:alice  tag:bug": "assignee_person.username = $2::VARCHAR) AND EXISTS (SELECT 1 FROM task_tag",
+	}
+	for query, expected := range valid {
+		q, err := ParseTaskQuery(query)
+		if err != nil {
+			t.Errorf("ParseTaskQuery(%q) returned error: %v", query, err)
+			continue
+		}
+		args := queryArgs{uint32(1)}
+		if sql := q.sql(&args); !strings.Contains(sql, expected) {
+			t.Errorf("ParseTaskQuery(%q) compiled to %q, expected it to contain %q", query, sql, expected)
+		}
+	}
+
+	invalid := map[string]string{
+		"tag:bug owner:alice":    "owner:alice",
+		"priority:huge":          "priority:huge",
+		"due:7d":                 "due:7d",
+		"due:<soon":              "due:<soon",
+		"assignee:<alice":        "assignee:<alice",
+		"estimate:<none":         "estimate:<none",
+		"tag:":                   "tag:",
+		`bug tag:"needs work`:    `tag:"needs work`,
+		`tag:"needs"work author`: `tag:"needs"work`,
+	}
+	for query, token := range invalid {
+		_, err := ParseTaskQuery(query)
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) || !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("ParseTaskQuery(%q) returned %v, expected QueryError", query, err)
+		} else if queryErr.Token != token || query[queryErr.Pos:queryErr.Pos+len(token)] != token {
+			t.Errorf("ParseTaskQuery(%q) reported token %q at %d, expected %q",
+				query, queryErr.Token, queryErr.Pos, token)
+		}
+	}
+}
+
+func TestBoardTaskQuery(t *testing.T) {
+	ctx := context.Background()
+	if err := db.System.RecreateAllTables(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mockedData, err := LoadMockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mockedData.CreateMockedPersons(); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := db.Board.Create(ctx, mockedData.Boards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := db.Tag.Create(ctx, Tag{Name: "tag", BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		task, err := db.Task.Create(ctx, Task{Name: fmt.Sprintf("task %d", i), Author: TaskAuthor(board.Owner),
+			BoardID: board.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Task.AddTagToTask(ctx, tag, task); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Task.AddAssigneeToTask(ctx, TaskAssignee(board.Owner), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	board, err = db.Board.GetByID(ctx, board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := board.Tasks
+
+	blocked, err := db.Tag.Create(ctx, Tag{Name: "Blocked", BoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Task.AddTagToTask(ctx, blocked, tasks[1]); err != nil {
+		t.Fatal(err)
+	}
+	high, soon, later := PriorityHigh, time.Now().Add(24*time.Hour), time.Now().Add(30*24*time.Hour)
+	for _, task := range tasks[:3] {
+		if _, err := db.Task.Update(ctx, task.ID, TaskUpdate{Priority: &high, DueAt: &soon}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Task.Update(ctx, tasks[2].ID, TaskUpdate{DueAt: &later}); err != nil {
+		t.Fatal(err)
+	}
+
+	query := fmt.Sprintf("assignee:%s tag:tag priority:high due:<7d -tag:blocked", board.Owner.Username)
+	q, err := ParseTaskQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filteredBoard, err := db.Board.GetByID(ctx, board.ID, WithQuery(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filteredBoard.Tasks) != 1 || filteredBoard.Tasks[0].ID != tasks[0].ID {
+		t.Errorf("Board filtered by %q has tasks %v, expected only task %d", query, filteredBoard.Tasks, tasks[0].ID)
+	}
+	if len(filteredBoard.Columns[0].Tasks) != 1 || len(filteredBoard.Tags) != 2 {
+		t.Errorf("Board filtered by %q has columns %v and tags %v, expected filtered columns and all tags",
+			query, filteredBoard.Columns, filteredBoard.Tags)
+	}
+
+	q, err = ParseTaskQuery(`"task 3"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	column, err := db.Column.GetByID(ctx, board.Columns[0].ID, WithQuery(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(column.Tasks) != 1 || column.Tasks[0].ID != tasks[3].ID {
+		t.Errorf("Column filtered by task name has tasks %v, expected only task %d", column.Tasks, tasks[3].ID)
+	}
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Kinds of errors returned by managers, use errors.Is to check kind of returned error.
+var (
+	// ErrNotFound - requested row doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict - row conflicts with existing data, e.g. username is already taken.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidInput - passed data is invalid, e.g. it references row that doesn't exist.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrForbidden - action is not allowed to person.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Error - error returned by managers, Kind is one of ErrNotFound, ErrConflict, ErrInvalidInput, ErrForbidden.
+// Err is original error of pgx if Error is caused by it.
+type Error struct {
+	Kind       error
+	Err        error
+	Message    string
+	Constraint string // name of violated constraint, if Error is caused by constraint violation
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is - reports if target is kind of e.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap - returns original error of pgx.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError - returns Error of kind with message formatted by format and args.
+func newError(kind error, format string, args ...any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// dbError - converts pgx.ErrNoRows and *pgconn.PgError in err to Error of matching kind,
+// returns other errors unchanged.
+func dbError(err error) error {
+	var dbErr *Error
+	if err == nil || errors.As(err, &dbErr) {
+		return err
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &Error{Kind: ErrNotFound, Err: err, Message: ErrNotFound.Error()}
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	message := pgErr.Detail
+	if message == "" {
+		message = pgErr.Message
+	}
+	dbErr = &Error{Err: err, Message: message, Constraint: pgErr.ConstraintName}
+
+	switch {
+	case pgErr.Code == "23505", // unique_violation
+		pgErr.Code == "40001", // serialization_failure
+		pgErr.Code == "40P01": // deadlock_detected
+		dbErr.Kind = ErrConflict
+	case pgErr.Code[:2] == "23", // other integrity_constraint_violation
+		pgErr.Code[:2] == "22": // data_exception
+		dbErr.Kind = ErrInvalidInput
+	default:
+		return err
+	}
+	return dbErr
+}
@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// migrationsLockID - key of advisory lock, held while migration is applied,
+// so several instances of GoKan started at once don't migrate concurrently.
+const migrationsLockID = 0x60CA4
+
+const (
+	lockMigrationsSQL = "SELECT pg_advisory_xact_lock($1);"
+
+	createVersionTableSQL = ("CREATE TABLE IF NOT EXISTS schema_migrations (" +
+		"version INTEGER PRIMARY KEY," +
+		"migration_name VARCHAR NOT NULL," +
+		"applied_at TIMESTAMPTZ NOT NULL DEFAULT now()" +
+		");")
+
+	insertVersionSQL = "INSERT INTO schema_migrations (version, migration_name) VALUES ($1, $2);"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Migration - versioned change of db schema, loaded from files
+// 'migrations/<version>_<name>.up.sql' and 'migrations/<version>_<name>.down.sql'.
+type Migration struct {
+	Name    string
+	UpSQL   string
+	DownSQL string
+	Version uint32
+}
+
+// Migrations - returns all embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	files, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("Migrations() -> %w", err)
+	}
+
+	byVersion := map[uint32]*Migration{}
+	for _, file := range files {
+		name := path.Base(file)
+
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction, name = "up", strings.TrimSuffix(name, ".up.sql")
+		case strings.HasSuffix(name, ".down.sql"):
+			direction, name = "down", strings.TrimSuffix(name, ".down.sql")
+		default:
+			return nil, fmt.Errorf("Migrations() -> %s: expected .up.sql or .down.sql suffix", file)
+		}
+
+		rawVersion, name, found := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(rawVersion, 10, 32)
+		if !found || err != nil || version == 0 {
+			return nil, fmt.Errorf("Migrations() -> %s: expected name in format <version>_<name>", file)
+		}
+
+		content, err := migrationsFS.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Migrations() -> %w", err)
+		}
+
+		migration, ok := byVersion[uint32(version)]
+		if !ok {
+			migration = &Migration{Name: name, Version: uint32(version)}
+			byVersion[uint32(version)] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("Migrations() -> %s: version %d is used by %s", file, version, migration.Name)
+		}
+
+		if direction == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" || migration.DownSQL == "" {
+			return nil, fmt.Errorf("Migrations() -> migration %d_%s must have both up and down files",
+				migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, migration := range migrations {
+		if migration.Version != uint32(i+1) {
+			return nil, fmt.Errorf("Migrations() -> migration %d is missing", i+1)
+		}
+	}
+	return migrations, nil
+}
+
+// MigrateUp - applies all pending migrations, returns count of applied migrations.
+func (sm SystemModel) MigrateUp(ctx context.Context) (int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, fmt.Errorf("MigrateUp() -> %w", err)
+	}
+
+	if err := sm.adoptSchema(ctx, migrations[0]); err != nil {
+		return 0, fmt.Errorf("MigrateUp() -> %w", err)
+	}
+
+	applied := 0
+	for {
+		done, err := sm.migrateStep(ctx, migrations, true)
+		if err != nil {
+			return applied, fmt.Errorf("MigrateUp() -> %w", err)
+		}
+		if done {
+			return applied, nil
+		}
+		applied++
+	}
+}
+
+// MigrateDown - reverts steps latest applied migrations, returns count of reverted migrations.
+func (sm SystemModel) MigrateDown(ctx context.Context, steps int) (int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, fmt.Errorf("MigrateDown() -> %w", err)
+	}
+
+	reverted := 0
+	for reverted < steps {
+		done, err := sm.migrateStep(ctx, migrations, false)
+		if err != nil {
+			return reverted, fmt.Errorf("MigrateDown() -> %w", err)
+		}
+		if done {
+			break
+		}
+		reverted++
+	}
+	return reverted, nil
+}
+
+// SchemaVersion - returns version of latest applied migration, 0 if no migrations applied.
+func (sm SystemModel) SchemaVersion(ctx context.Context) (uint32, error) {
+	isExist, err := sm.IsTableExist(ctx, "schema_migrations")
+	if err != nil {
+		return 0, fmt.Errorf("SchemaVersion() -> %w", err)
+	}
+	if !isExist {
+		return 0, nil
+	}
+
+	version, err := schemaVersion(ctx, sm.DB)
+	if err != nil {
+		return 0, fmt.Errorf("SchemaVersion() -> %w", err)
+	}
+	return version, nil
+}
+
+// adoptSchema - records baseline migration as applied without executing it, if db was created
+// before migrations were introduced: it has 'person' and 'board' tables, but no 'schema_migrations'.
+func (sm SystemModel) adoptSchema(ctx context.Context, baseline Migration) error {
+	const isUnversionedSQL = ("SELECT to_regclass('public.schema_migrations') IS NULL " +
+		"AND to_regclass('public.person') IS NOT NULL " +
+		"AND to_regclass('public.board') IS NOT NULL;")
+
+	err := inTx(ctx, sm.DB, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, lockMigrationsSQL, migrationsLockID); err != nil {
+			return err
+		}
+
+		var isUnversioned bool
+		if err := tx.QueryRow(ctx, isUnversionedSQL).Scan(&isUnversioned); err != nil || !isUnversioned {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, createVersionTableSQL); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, insertVersionSQL, baseline.Version, baseline.Name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("adoptSchema() -> %w", err)
+	}
+	return nil
+}
+
+// migrateStep - applies next pending migration if up is true, else reverts latest applied migration.
+// Migration is executed in transaction holding migrations lock, returns true if there is nothing to do.
+func (sm SystemModel) migrateStep(ctx context.Context, migrations []Migration, up bool) (bool, error) {
+	const deleteVersionSQL = "DELETE FROM schema_migrations WHERE version = $1;"
+
+	done := false
+	err := inTx(ctx, sm.DB, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, lockMigrationsSQL, migrationsLockID); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, createVersionTableSQL); err != nil {
+			return err
+		}
+
+		version, err := schemaVersion(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if int(version) > len(migrations) {
+			return fmt.Errorf("schema version %d is newer than latest known migration %d", version, len(migrations))
+		}
+
+		if (up && int(version) == len(migrations)) || (!up && version == 0) {
+			done = true
+			return nil
+		}
+
+		if up {
+			migration := migrations[version]
+			if _, err := tx.Exec(ctx, migration.UpSQL); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			_, err = tx.Exec(ctx, insertVersionSQL, migration.Version, migration.Name)
+			return err
+		}
+
+		migration := migrations[version-1]
+		if _, err := tx.Exec(ctx, migration.DownSQL); err != nil {
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err = tx.Exec(ctx, deleteVersionSQL, migration.Version)
+		return err
+	})
+	return done, err
+}
+
+// schemaVersion - returns max version from schema_migrations table.
+func schemaVersion(ctx context.Context, dbConn DBConn) (uint32, error) {
+	var version uint32
+	err := dbConn.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations;").Scan(&version)
+	return version, err
+}
@@ -0,0 +1,8 @@
+DROP TABLE contributor;
+DROP TABLE task_tag;
+DROP TABLE tag;
+DROP TABLE subtask;
+DROP TABLE assignee;
+DROP TABLE task;
+DROP TABLE board;
+DROP TABLE person;
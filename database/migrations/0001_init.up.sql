@@ -0,0 +1,56 @@
+CREATE TABLE person (
+    person_id serial PRIMARY KEY,
+    username VARCHAR UNIQUE NOT NULL,
+    first_name VARCHAR NOT NULL,
+    last_name VARCHAR NOT NULL,
+    email VARCHAR UNIQUE NOT NULL,
+    password_hash VARCHAR NOT NULL
+);
+
+CREATE TABLE board (
+    board_id serial PRIMARY KEY,
+    board_name VARCHAR NOT NULL,
+    owner_id INTEGER REFERENCES person (person_id) ON DELETE CASCADE NOT NULL
+);
+
+CREATE TABLE task (
+    task_id serial PRIMARY KEY,
+    task_name VARCHAR NOT NULL,
+    task_description VARCHAR,
+    board_id INTEGER REFERENCES board (board_id) ON DELETE CASCADE,
+    author_id INTEGER REFERENCES person (person_id) ON DELETE SET NULL NOT NULL
+);
+
+CREATE TABLE assignee (
+    ref_task_id INTEGER REFERENCES task (task_id) ON DELETE CASCADE NOT NULL,
+    assignee_id INTEGER REFERENCES person (person_id) ON DELETE CASCADE NOT NULL,
+    CONSTRAINT assignee_pkey PRIMARY KEY (ref_task_id, assignee_id)
+);
+
+CREATE TABLE subtask (
+    subtask_id serial PRIMARY KEY,
+    subtask_name VARCHAR NOT NULL,
+    parent_task_id INTEGER REFERENCES task (task_id) ON DELETE CASCADE NOT NULL
+);
+
+CREATE TABLE tag (
+    tag_id serial PRIMARY KEY,
+    tag_name VARCHAR NOT NULL,
+    tag_description VARCHAR NOT NULL,
+    board_id INTEGER REFERENCES board (board_id) ON DELETE CASCADE
+);
+
+CREATE TABLE task_tag (
+    ref_task_id INTEGER REFERENCES task (task_id) ON DELETE CASCADE,
+    ref_tag_id INTEGER REFERENCES tag (tag_id) ON DELETE CASCADE,
+    CONSTRAINT task_tag_pkey PRIMARY KEY (ref_task_id, ref_tag_id)
+);
+
+CREATE TABLE contributor (
+    person_id INTEGER REFERENCES person (person_id) ON DELETE CASCADE,
+    board_id INTEGER REFERENCES board (board_id) ON DELETE CASCADE,
+    CONSTRAINT contributor_pkey PRIMARY KEY (person_id, board_id)
+);
+
+INSERT INTO person (person_id, username, first_name, last_name, email, password_hash)
+VALUES (0, 'null', 'null', 'null', 'null', 'null');
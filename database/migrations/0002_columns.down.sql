@@ -0,0 +1,7 @@
+ALTER TABLE task
+    DROP CONSTRAINT task_column_fkey,
+    DROP CONSTRAINT task_position_key,
+    DROP COLUMN task_position,
+    DROP COLUMN column_id;
+
+DROP TABLE "column";
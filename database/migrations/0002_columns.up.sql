@@ -0,0 +1,34 @@
+CREATE TABLE "column" (
+    column_id serial PRIMARY KEY,
+    column_name VARCHAR NOT NULL,
+    board_id INTEGER REFERENCES board (board_id) ON DELETE CASCADE NOT NULL,
+    column_position INTEGER NOT NULL,
+    CONSTRAINT column_board_key UNIQUE (column_id, board_id)
+);
+
+-- existing boards get default columns, their tasks are placed in the first one in order of creation
+INSERT INTO "column" (column_name, board_id, column_position)
+SELECT default_column.column_name, board.board_id, default_column.column_position
+FROM board, (VALUES ('To Do', 1), ('In Progress', 2), ('Done', 3)) AS default_column(column_name, column_position);
+
+-- tasks without board aren't reachable and can't be placed in column
+DELETE FROM task WHERE board_id IS NULL;
+
+ALTER TABLE task
+    ADD COLUMN column_id INTEGER,
+    ADD COLUMN task_position BIGINT;
+
+UPDATE task SET column_id = placed_task.column_id, task_position = placed_task.task_position
+FROM (
+    SELECT task.task_id, "column".column_id,
+        row_number() OVER (PARTITION BY task.board_id ORDER BY task.task_id) * 65536 AS task_position
+    FROM task JOIN "column" ON "column".board_id = task.board_id AND "column".column_position = 1
+) AS placed_task
+WHERE task.task_id = placed_task.task_id;
+
+ALTER TABLE task
+    ALTER COLUMN column_id SET NOT NULL,
+    ALTER COLUMN task_position SET NOT NULL,
+    ADD CONSTRAINT task_position_key UNIQUE (column_id, task_position),
+    ADD CONSTRAINT task_column_fkey FOREIGN KEY (column_id, board_id)
+        REFERENCES "column" (column_id, board_id) ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+CREATE TABLE session (
+    session_id serial PRIMARY KEY,
+    token_hash VARCHAR UNIQUE NOT NULL,
+    person_id INTEGER REFERENCES person (person_id) ON DELETE CASCADE NOT NULL,
+    session_kind VARCHAR NOT NULL CHECK (session_kind IN ('cookie', 'token')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
@@ -0,0 +1 @@
+ALTER TABLE contributor DROP COLUMN contributor_role;
@@ -0,0 +1,4 @@
+-- existing contributors keep their access as members
+ALTER TABLE contributor
+    ADD COLUMN contributor_role VARCHAR NOT NULL DEFAULT 'member'
+        CHECK (contributor_role IN ('admin', 'member', 'viewer'));
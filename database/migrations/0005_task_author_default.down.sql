@@ -0,0 +1,5 @@
+ALTER TABLE task
+    DROP CONSTRAINT task_author_id_fkey,
+    ADD CONSTRAINT task_author_id_fkey FOREIGN KEY (author_id)
+        REFERENCES person (person_id) ON DELETE SET NULL,
+    ALTER COLUMN author_id DROP DEFAULT;
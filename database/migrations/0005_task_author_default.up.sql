@@ -0,0 +1,6 @@
+-- null person becomes author of tasks whose author was deleted
+ALTER TABLE task
+    ALTER COLUMN author_id SET DEFAULT 0,
+    DROP CONSTRAINT task_author_id_fkey,
+    ADD CONSTRAINT task_author_id_fkey FOREIGN KEY (author_id)
+        REFERENCES person (person_id) ON DELETE SET DEFAULT;
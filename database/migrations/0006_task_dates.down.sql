@@ -0,0 +1,6 @@
+DROP INDEX task_board_due_idx;
+
+ALTER TABLE task
+    DROP CONSTRAINT task_dates_check,
+    DROP COLUMN due_at,
+    DROP COLUMN start_at;
@@ -0,0 +1,6 @@
+ALTER TABLE task
+    ADD COLUMN start_at TIMESTAMPTZ,
+    ADD COLUMN due_at TIMESTAMPTZ,
+    ADD CONSTRAINT task_dates_check CHECK (start_at <= due_at);
+
+CREATE INDEX task_board_due_idx ON task (board_id, due_at) WHERE due_at IS NOT NULL;
@@ -0,0 +1,13 @@
+DROP TRIGGER tag_updated_at ON tag;
+DROP TRIGGER subtask_updated_at ON subtask;
+DROP TRIGGER task_updated_at ON task;
+DROP TRIGGER board_updated_at ON board;
+DROP TRIGGER person_updated_at ON person;
+
+ALTER TABLE tag DROP COLUMN updated_at, DROP COLUMN created_at;
+ALTER TABLE subtask DROP COLUMN updated_at, DROP COLUMN created_at;
+ALTER TABLE task DROP COLUMN last_modified_by, DROP COLUMN updated_at, DROP COLUMN created_at;
+ALTER TABLE board DROP COLUMN updated_at, DROP COLUMN created_at;
+ALTER TABLE person DROP COLUMN updated_at, DROP COLUMN created_at;
+
+DROP FUNCTION set_updated_at();
@@ -0,0 +1,3 @@
+ALTER TABLE task
+    DROP COLUMN estimate,
+    DROP COLUMN task_priority;
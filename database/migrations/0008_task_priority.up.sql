@@ -0,0 +1,4 @@
+ALTER TABLE task
+    ADD COLUMN task_priority VARCHAR NOT NULL DEFAULT 'none'
+        CHECK (task_priority IN ('none', 'low', 'medium', 'high', 'urgent')),
+    ADD COLUMN estimate DOUBLE PRECISION CHECK (estimate >= 0);
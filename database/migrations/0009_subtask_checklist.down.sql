@@ -0,0 +1,6 @@
+DROP INDEX subtask_parent_task_idx;
+
+ALTER TABLE subtask
+    DROP COLUMN assignee_id,
+    DROP COLUMN subtask_position,
+    DROP COLUMN is_done;
@@ -0,0 +1,16 @@
+ALTER TABLE subtask
+    ADD COLUMN is_done BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN subtask_position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN assignee_id INTEGER REFERENCES person (person_id) ON DELETE SET NULL;
+
+-- existing subtasks are ordered by creation, numbering isn't a change of subtask
+ALTER TABLE subtask DISABLE TRIGGER subtask_updated_at;
+UPDATE subtask SET subtask_position = ordered.position
+FROM (
+    SELECT subtask_id, row_number() OVER (PARTITION BY parent_task_id ORDER BY subtask_id) AS position
+    FROM subtask
+) AS ordered
+WHERE subtask.subtask_id = ordered.subtask_id;
+ALTER TABLE subtask ENABLE TRIGGER subtask_updated_at;
+
+CREATE INDEX subtask_parent_task_idx ON subtask (parent_task_id, subtask_position);
@@ -0,0 +1,18 @@
+CREATE TABLE comment (
+    comment_id serial PRIMARY KEY,
+    task_id INTEGER REFERENCES task (task_id) ON DELETE CASCADE NOT NULL,
+    author_id INTEGER DEFAULT 0 REFERENCES person (person_id) ON DELETE SET DEFAULT NOT NULL,
+    reply_to_id INTEGER,
+    comment_text VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT comment_task_key UNIQUE (comment_id, task_id),
+    -- reply belongs to the same task as replied comment and is deleted together with it
+    CONSTRAINT comment_reply_to_fkey FOREIGN KEY (reply_to_id, task_id)
+        REFERENCES comment (comment_id, task_id) ON DELETE CASCADE
+);
+
+CREATE INDEX comment_task_idx ON comment (task_id, comment_id);
+
+CREATE TRIGGER comment_updated_at BEFORE UPDATE ON comment
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,13 @@
+DROP TRIGGER comment_activity ON comment;
+DROP TRIGGER task_tag_activity ON task_tag;
+DROP TRIGGER assignee_activity ON assignee;
+DROP TRIGGER subtask_activity ON subtask;
+DROP TRIGGER task_activity ON task;
+DROP TRIGGER contributor_activity ON contributor;
+DROP TRIGGER tag_activity ON tag;
+DROP TRIGGER column_activity ON "column";
+DROP TRIGGER board_activity ON board;
+DROP FUNCTION log_activity();
+DROP FUNCTION activity_actor();
+DROP TABLE activity;
+DROP FUNCTION forbid_activity_change();
@@ -0,0 +1,117 @@
+-- append-only log of changes of boards and their entities, written by triggers in the same
+-- transaction as the change, moves of tasks and subtasks are written by GoKan itself.
+-- There are no foreign keys, so history outlives boards, tasks and persons it mentions.
+CREATE TABLE activity (
+    activity_id serial PRIMARY KEY,
+    board_id INTEGER NOT NULL,
+    task_id INTEGER,
+    actor_id INTEGER,
+    activity_verb VARCHAR NOT NULL CHECK (activity_verb IN ('create', 'update', 'delete', 'move')),
+    entity_type VARCHAR NOT NULL,
+    entity_id INTEGER NOT NULL,
+    before_data JSONB,
+    after_data JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX activity_board_idx ON activity (board_id, activity_id);
+CREATE INDEX activity_task_idx ON activity (task_id, activity_id) WHERE task_id IS NOT NULL;
+
+CREATE FUNCTION forbid_activity_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'activity is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER activity_append_only BEFORE UPDATE OR DELETE ON activity
+    FOR EACH ROW EXECUTE FUNCTION forbid_activity_change();
+
+-- person making changes in current transaction, set by GoKan from WithActor
+CREATE FUNCTION activity_actor() RETURNS INTEGER AS $$
+    SELECT NULLIF(current_setting('gokan.actor_id', true), '')::INTEGER;
+$$ LANGUAGE sql STABLE;
+
+-- log_activity(entity_id_column, task_id_column) - writes change of row to activity,
+-- task_id_column is empty for rows not related to task.
+CREATE FUNCTION log_activity() RETURNS TRIGGER AS $$
+DECLARE
+    -- positions are changed by renumbering too, so moves are logged by GoKan instead
+    ignored_keys CONSTANT TEXT[] := ARRAY[
+        'updated_at', 'last_modified_by', 'column_id', 'task_position', 'subtask_position'];
+    before_row JSONB;
+    after_row JSONB;
+    entity_row JSONB;
+    activity_board_id INTEGER;
+    activity_task_id INTEGER;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        before_row := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        after_row := to_jsonb(NEW);
+    END IF;
+    entity_row := COALESCE(after_row, before_row);
+
+    IF TG_OP = 'UPDATE' AND before_row - ignored_keys = after_row - ignored_keys THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_ARGV[1] <> '' THEN
+        activity_task_id := (entity_row ->> TG_ARGV[1])::INTEGER;
+    END IF;
+
+    IF TG_TABLE_NAME = 'board' THEN
+        activity_board_id := (entity_row ->> 'board_id')::INTEGER;
+    ELSIF entity_row ? 'board_id' THEN
+        SELECT board_id INTO activity_board_id FROM board
+        WHERE board_id = (entity_row ->> 'board_id')::INTEGER;
+    ELSE
+        SELECT board_id INTO activity_board_id FROM task WHERE task_id = activity_task_id;
+    END IF;
+
+    -- rows deleted together with their parent aren't logged, deletion of parent is logged instead
+    IF activity_board_id IS NULL OR TG_OP = 'DELETE' AND (
+        TG_TABLE_NAME = 'task' AND NOT EXISTS (
+            SELECT 1 FROM "column" WHERE column_id = (before_row ->> 'column_id')::INTEGER)
+        OR TG_TABLE_NAME = 'task_tag' AND NOT EXISTS (
+            SELECT 1 FROM tag WHERE tag_id = (before_row ->> 'ref_tag_id')::INTEGER)
+        OR TG_TABLE_NAME = 'comment' AND before_row ->> 'reply_to_id' IS NOT NULL AND NOT EXISTS (
+            SELECT 1 FROM comment WHERE comment_id = (before_row ->> 'reply_to_id')::INTEGER)
+    ) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO activity
+        (board_id, task_id, actor_id, activity_verb, entity_type, entity_id, before_data, after_data)
+    VALUES (
+        activity_board_id,
+        activity_task_id,
+        activity_actor(),
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        TG_TABLE_NAME,
+        (entity_row ->> TG_ARGV[0])::INTEGER,
+        before_row,
+        after_row
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER board_activity AFTER INSERT OR UPDATE OR DELETE ON board
+    FOR EACH ROW EXECUTE FUNCTION log_activity('board_id', '');
+CREATE TRIGGER column_activity AFTER INSERT OR UPDATE OR DELETE ON "column"
+    FOR EACH ROW EXECUTE FUNCTION log_activity('column_id', '');
+CREATE TRIGGER tag_activity AFTER INSERT OR UPDATE OR DELETE ON tag
+    FOR EACH ROW EXECUTE FUNCTION log_activity('tag_id', '');
+CREATE TRIGGER contributor_activity AFTER INSERT OR UPDATE OR DELETE ON contributor
+    FOR EACH ROW EXECUTE FUNCTION log_activity('person_id', '');
+CREATE TRIGGER task_activity AFTER INSERT OR UPDATE OR DELETE ON task
+    FOR EACH ROW EXECUTE FUNCTION log_activity('task_id', 'task_id');
+CREATE TRIGGER subtask_activity AFTER INSERT OR UPDATE OR DELETE ON subtask
+    FOR EACH ROW EXECUTE FUNCTION log_activity('subtask_id', 'parent_task_id');
+CREATE TRIGGER assignee_activity AFTER INSERT OR UPDATE OR DELETE ON assignee
+    FOR EACH ROW EXECUTE FUNCTION log_activity('assignee_id', 'ref_task_id');
+CREATE TRIGGER task_tag_activity AFTER INSERT OR UPDATE OR DELETE ON task_tag
+    FOR EACH ROW EXECUTE FUNCTION log_activity('ref_tag_id', 'ref_task_id');
+CREATE TRIGGER comment_activity AFTER INSERT OR UPDATE OR DELETE ON comment
+    FOR EACH ROW EXECUTE FUNCTION log_activity('comment_id', 'task_id');
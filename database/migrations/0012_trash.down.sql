@@ -0,0 +1,12 @@
+-- trash is purged, positions of tasks in trash may conflict with other tasks
+DELETE FROM task WHERE deleted_at IS NOT NULL;
+DELETE FROM board WHERE deleted_at IS NOT NULL;
+
+DROP INDEX task_position_key;
+ALTER TABLE task ADD CONSTRAINT task_position_key UNIQUE (column_id, task_position);
+
+DROP INDEX task_deleted_at_idx;
+DROP INDEX board_deleted_at_idx;
+
+ALTER TABLE task DROP COLUMN deleted_at;
+ALTER TABLE board DROP COLUMN deleted_at;
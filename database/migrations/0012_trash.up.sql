@@ -0,0 +1,10 @@
+-- boards and tasks are moved to trash by setting deleted_at, and are purged from it after retention period
+ALTER TABLE board ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE task ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX board_deleted_at_idx ON board (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX task_deleted_at_idx ON task (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- tasks in trash keep their positions, which may be taken by other tasks after deletion
+ALTER TABLE task DROP CONSTRAINT task_position_key;
+CREATE UNIQUE INDEX task_position_key ON task (column_id, task_position) WHERE deleted_at IS NULL;
@@ -0,0 +1,19 @@
+-- archived tasks become active, they are placed after other tasks of their columns
+UPDATE task SET task_position = ranked.task_position
+FROM (
+    SELECT archived_task.task_id,
+        (SELECT COALESCE(MAX(active_task.task_position), 0) FROM task AS active_task
+        WHERE active_task.column_id = archived_task.column_id
+            AND active_task.deleted_at IS NULL AND active_task.archived_at IS NULL)
+        + row_number() OVER (PARTITION BY archived_task.column_id ORDER BY archived_task.task_position)
+        * 65536 AS task_position
+    FROM task AS archived_task
+    WHERE archived_task.archived_at IS NOT NULL AND archived_task.deleted_at IS NULL
+) AS ranked
+WHERE task.task_id = ranked.task_id;
+
+DROP INDEX task_position_key;
+CREATE UNIQUE INDEX task_position_key ON task (column_id, task_position) WHERE deleted_at IS NULL;
+
+ALTER TABLE task DROP COLUMN archived_at;
+ALTER TABLE board DROP COLUMN archived_at;
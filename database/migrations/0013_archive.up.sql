@@ -0,0 +1,8 @@
+-- archived boards and tasks are hidden from active views, but are kept with all their data
+ALTER TABLE board ADD COLUMN archived_at TIMESTAMPTZ;
+ALTER TABLE task ADD COLUMN archived_at TIMESTAMPTZ;
+
+-- archived tasks keep their positions like tasks in trash
+DROP INDEX task_position_key;
+CREATE UNIQUE INDEX task_position_key ON task (column_id, task_position)
+    WHERE deleted_at IS NULL AND archived_at IS NULL;
@@ -0,0 +1,8 @@
+DROP TRIGGER comment_search ON comment;
+DROP TRIGGER subtask_search ON subtask;
+DROP TRIGGER task_search ON task;
+
+DROP FUNCTION refresh_task_search_trigger();
+DROP FUNCTION refresh_task_search(INTEGER);
+
+DROP TABLE task_search;
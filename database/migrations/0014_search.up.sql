@@ -0,0 +1,53 @@
+-- full-text search document of task: name, description, names of subtasks and text of comments,
+-- weighted from A to D in this order. Documents are kept in separate table and refreshed by triggers,
+-- so refreshing them doesn't touch updated_at of tasks and isn't written to activity.
+CREATE TABLE task_search (
+    task_id INTEGER PRIMARY KEY REFERENCES task (task_id) ON DELETE CASCADE,
+    document TSVECTOR NOT NULL
+);
+
+CREATE INDEX task_search_document_idx ON task_search USING GIN (document);
+
+CREATE FUNCTION refresh_task_search(ref_task_id INTEGER) RETURNS VOID AS $$
+    INSERT INTO task_search (task_id, document)
+    SELECT task.task_id,
+        setweight(to_tsvector('english', task.task_name), 'A') ||
+        setweight(to_tsvector('english', COALESCE(task.task_description, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE((SELECT string_agg(subtask.subtask_name, E'\n')
+            FROM subtask WHERE subtask.parent_task_id = task.task_id), '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE((SELECT string_agg(comment.comment_text, E'\n')
+            FROM comment WHERE comment.task_id = task.task_id), '')), 'D')
+    FROM task WHERE task.task_id = ref_task_id
+    ON CONFLICT (task_id) DO UPDATE SET document = EXCLUDED.document;
+$$ LANGUAGE sql;
+
+-- refresh_task_search_trigger(task_id_column) - refreshes document of task of changed row,
+-- document isn't refreshed for rows deleted together with their task.
+CREATE FUNCTION refresh_task_search_trigger() RETURNS TRIGGER AS $$
+DECLARE
+    old_task_id INTEGER;
+    new_task_id INTEGER;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_task_id := (to_jsonb(OLD) ->> TG_ARGV[0])::INTEGER;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_task_id := (to_jsonb(NEW) ->> TG_ARGV[0])::INTEGER;
+        PERFORM refresh_task_search(new_task_id);
+    END IF;
+
+    IF old_task_id IS DISTINCT FROM new_task_id THEN
+        PERFORM refresh_task_search(old_task_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_search AFTER INSERT OR UPDATE OF task_name, task_description ON task
+    FOR EACH ROW EXECUTE FUNCTION refresh_task_search_trigger('task_id');
+CREATE TRIGGER subtask_search AFTER INSERT OR DELETE OR UPDATE OF subtask_name, parent_task_id ON subtask
+    FOR EACH ROW EXECUTE FUNCTION refresh_task_search_trigger('parent_task_id');
+CREATE TRIGGER comment_search AFTER INSERT OR DELETE OR UPDATE OF comment_text, task_id ON comment
+    FOR EACH ROW EXECUTE FUNCTION refresh_task_search_trigger('task_id');
+
+SELECT refresh_task_search(task_id) FROM task;
@@ -0,0 +1,4 @@
+ALTER TABLE board
+    DROP CONSTRAINT board_owner_id_fkey,
+    ADD CONSTRAINT board_owner_id_fkey FOREIGN KEY (owner_id)
+        REFERENCES person (person_id) ON DELETE CASCADE;
@@ -0,0 +1,6 @@
+-- boards are transferred to successors or moved to trash before their owner is deleted,
+-- so deleting person never deletes boards
+ALTER TABLE board
+    DROP CONSTRAINT board_owner_id_fkey,
+    ADD CONSTRAINT board_owner_id_fkey FOREIGN KEY (owner_id)
+        REFERENCES person (person_id) ON DELETE RESTRICT;
@@ -0,0 +1,148 @@
+package database
+
+// LoadOption - option of Get methods, defines which related data is loaded together with model.
+// Get methods called without options load all related data.
+type LoadOption func(*loadOptions)
+
+// loadOptions - related data to load, options of board and person are also applied
+// to their tasks, e.g. WithTags loads both Board.Tags and Task.Tags of board tasks.
+type loadOptions struct {
+	query         *TaskQuery // only tasks matching query are loaded, if set
+	tasks         bool
+	tags          bool
+	columns       bool
+	contributors  bool
+	subtasks      bool
+	assignees     bool
+	boards        bool
+	assignedTasks bool
+	archived      bool // archived tasks are loaded too
+	noRelations   bool // set by WithoutRelations, so it isn't confused with options selecting no related data
+}
+
+// WithTasks - loads Board.Tasks and Column.Tasks.
+func WithTasks() LoadOption {
+	return func(o *loadOptions) { o.tasks = true }
+}
+
+// WithTags - loads Board.Tags and Task.Tags.
+func WithTags() LoadOption {
+	return func(o *loadOptions) { o.tags = true }
+}
+
+// WithColumns - loads Board.Columns, columns are filled with tasks only if WithTasks is passed too.
+func WithColumns() LoadOption {
+	return func(o *loadOptions) { o.columns = true }
+}
+
+// WithContributors - loads Board.Contributors.
+func WithContributors() LoadOption {
+	return func(o *loadOptions) { o.contributors = true }
+}
+
+// WithSubtasks - loads Task.Subtasks.
+func WithSubtasks() LoadOption {
+	return func(o *loadOptions) { o.subtasks = true }
+}
+
+// WithAssignees - loads Task.Assignees.
+func WithAssignees() LoadOption {
+	return func(o *loadOptions) { o.assignees = true }
+}
+
+// WithBoards - loads Person.Boards.
+func WithBoards() LoadOption {
+	return func(o *loadOptions) { o.boards = true }
+}
+
+// WithAssignedTasks - loads Person.AssignedTasks.
+func WithAssignedTasks() LoadOption {
+	return func(o *loadOptions) { o.assignedTasks = true }
+}
+
+// WithEverything - loads all related data, same as passing no options.
+func WithEverything() LoadOption {
+	return func(o *loadOptions) { *o = everything(*o) }
+}
+
+// WithoutRelations - loads only model itself without any related data.
+func WithoutRelations() LoadOption {
+	return func(o *loadOptions) { *o = loadOptions{archived: o.archived, noRelations: true, query: o.query} }
+}
+
+// WithArchived - loads archived tasks together with active ones, which are only loaded by default.
+// It doesn't select related data, so passed alone it loads everything like no options.
+func WithArchived() LoadOption {
+	return func(o *loadOptions) { o.archived = true }
+}
+
+// WithQuery - loads only tasks matching q in Board.Tasks, Board.Columns and Column.Tasks.
+// Like WithArchived, it doesn't select related data.
+func WithQuery(q TaskQuery) LoadOption {
+	return func(o *loadOptions) { o.query = &q }
+}
+
+// newLoadOptions - returns loadOptions set by opts, or loadOptions with everything
+// if opts don't select related data.
+func newLoadOptions(opts []LoadOption) loadOptions {
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o == (loadOptions{archived: o.archived, query: o.query}) {
+		return everything(o)
+	}
+	return o
+}
+
+// tasksCondition - returns condition selecting tasks loaded by o, adding its values to args.
+func (o loadOptions) tasksCondition(args *queryArgs) string {
+	condition := "(" + args.add(o.archived) + "::BOOLEAN OR task.archived_at IS NULL)"
+	if o.query != nil {
+		condition += " AND " + o.query.sql(args)
+	}
+	return condition
+}
+
+// everything - returns loadOptions with all related data, loading tasks like o.
+func everything(o loadOptions) loadOptions {
+	return loadOptions{
+		tasks:         true,
+		tags:          true,
+		columns:       true,
+		contributors:  true,
+		subtasks:      true,
+		assignees:     true,
+		boards:        true,
+		assignedTasks: true,
+		archived:      o.archived,
+		query:         o.query,
+	}
+}
+
+// Page - options of paginated Get methods, page holds up to Limit rows following row with ID AfterID
+// in order of the method, so ID of the last row of page is AfterID of the next page. First page has AfterID 0.
+type Page struct {
+	AfterID uint32
+	Limit   int // DefaultPageLimit if not set, can't be greater than MaxPageLimit
+}
+
+const (
+	// DefaultPageLimit - count of rows in Page with zero Limit.
+	DefaultPageLimit = 50
+	// MaxPageLimit - max count of rows in Page.
+	MaxPageLimit = 200
+)
+
+// limit - returns count of rows in page.
+func (p Page) limit() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageLimit
+	case p.Limit > MaxPageLimit:
+		return MaxPageLimit
+	default:
+		return p.Limit
+	}
+}
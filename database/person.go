@@ -3,18 +3,33 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // Person - person model struct.
 type Person struct {
-	Username      string       `json:"username"`
-	FirstName     string       `json:"first_name"`
-	LastName      string       `json:"last_name"`
-	Email         string       `json:"email"`
-	PasswordHash  string       `json:"password_hash"`
-	Boards        []SmallBoard // LoadPersonBoards(), by board_id from contributor table
-	AssignedTasks []Task       // LoadPersonAssignedTasks(), from executor_id in task
-	ID            uint32       `json:"person_id"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	Username       string       `json:"username"`
+	FirstName      string       `json:"first_name"`
+	LastName       string       `json:"last_name"`
+	Email          string       `json:"email"`
+	PasswordHash   string       `json:"password_hash"`
+	Boards         []SmallBoard `json:"boards"`          // LoadPersonBoards(), by board_id from contributor table
+	ArchivedBoards []SmallBoard `json:"archived_boards"` // loaded together with Boards
+	AssignedTasks  []Task       `json:"assigned_tasks"`  // LoadPersonAssignedTasks(), from executor_id in task
+	ID             uint32       `json:"person_id"`
+}
+
+// personColumns - columns of person table, scanned by personFields.
+const personColumns = ("person.person_id, person.username, person.first_name, person.last_name, " +
+	"person.email, person.password_hash, person.created_at, person.updated_at")
+
+// personFields - returns destinations for scanning row selected by personColumns into p.
+func personFields(p *Person) []any {
+	return []any{&p.ID, &p.Username, &p.FirstName, &p.LastName, &p.Email, &p.PasswordHash, &p.CreatedAt, &p.UpdatedAt}
 }
 
 // SmallPerson - is a struct, that used to save person data in some other structs, when
@@ -23,16 +38,35 @@ type SmallPerson struct {
 	Username  string `json:"username"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
+	Email     string `json:"-"` // private, shown only in responses to person themselves
 	ID        uint32 `json:"person_id"`
 }
 
+// smallPersonColumns - columns of person table, scanned by smallPersonFields.
+const smallPersonColumns = "person.person_id, person.username, person.first_name, person.last_name, person.email"
+
+// smallPersonFields - returns destinations for scanning row selected by smallPersonColumns into p.
+func smallPersonFields(p *SmallPerson) []any {
+	return []any{&p.ID, &p.Username, &p.FirstName, &p.LastName, &p.Email}
+}
+
 // Small - return SmallPerson representation of Person.
 func (p Person) Small() SmallPerson {
 	return SmallPerson{Username: p.Username, FirstName: p.FirstName,
 		LastName: p.LastName, Email: p.Email, ID: p.ID}
 }
 
+// AsContributor - returns Contributor representation of Person with role.
+func (p Person) AsContributor(role ContributorRole) Contributor {
+	return p.Small().AsContributor(role)
+}
+
+// AsContributor - returns Contributor representation of SmallPerson with role.
+func (p SmallPerson) AsContributor(role ContributorRole) Contributor {
+	return Contributor{Username: p.Username, FirstName: p.FirstName,
+		LastName: p.LastName, Email: p.Email, Role: role, ID: p.ID}
+}
+
 // IsContributor - checks if p of type Person represent same row from db as contrib of type Contributor.
 func (p *Person) IsContributor(contrib Contributor) bool {
 	isEqual := true
@@ -53,6 +87,14 @@ func (p *Person) IsContributor(contrib Contributor) bool {
 	return isEqual
 }
 
+// PersonUpdate - fields to change in PersonModel.Update, nil fields are left unchanged.
+type PersonUpdate struct {
+	Username  *string `json:"username"`
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+	Email     *string `json:"email"`
+}
+
 // PersonModel - struct that implements PersonManager interface for interacting with person table in db.
 type PersonModel struct {
 	DB DBConn
@@ -63,197 +105,265 @@ type PersonModel struct {
 func (pm PersonModel) Create(ctx context.Context, person Person) (Person, error) {
 	sql := ("INSERT INTO " +
 		"person (username, first_name, last_name, email, password_hash) " +
-		"VALUES ($1, $2, $3, $4, $5)" +
-		"RETURNING *;")
+		"VALUES ($1, $2, $3, $4, $5) " +
+		"RETURNING " + personColumns + ";")
 
-	var createdPerson Person
-	err := pm.DB.QueryRow(ctx, sql,
+	createdPerson, err := queryRow(ctx, pm.DB, personFields, sql,
 		person.Username,
 		person.FirstName,
 		person.LastName,
 		person.Email,
 		person.PasswordHash,
-	).Scan(
-		&createdPerson.ID,
-		&createdPerson.Username,
-		&createdPerson.FirstName,
-		&createdPerson.LastName,
-		&createdPerson.Email,
-		&createdPerson.PasswordHash,
 	)
 
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.Create() -> %w", err)
+		return Person{}, fmt.Errorf("PersonModel.Create() -> %w", dbError(err))
 	}
 
 	return createdPerson, nil
 }
 
-// DeleteByID - deletes row from table 'person'.
-func (pm PersonModel) DeleteByID(ctx context.Context, personID uint32) error {
-	sql := "DELETE FROM person WHERE person_id = $1;"
-	_, err := pm.DB.Exec(ctx, sql, personID)
+// DeleteByID - deletes row from table 'person'. Boards owned by person are transferred to successors
+// chosen by board ID: board with contributors only to its contributor, boards without contributors and
+// boards in trash to any person. Returns ErrConflict if board with contributors has no successor,
+// other boards without successor are left to null person in trash.
+// Tasks authored by person are left with null person as author, sessions of person are revoked.
+func (pm PersonModel) DeleteByID(ctx context.Context, personID uint32, successors map[uint32]uint32) error {
+	type ownedBoard struct {
+		ID              uint32
+		IsDeleted       bool
+		HasContributors bool
+	}
+
+	const (
+		ownedBoardsSQL = ("SELECT board_id, deleted_at IS NOT NULL, " +
+			"EXISTS (SELECT 1 FROM contributor WHERE contributor.board_id = board.board_id) " +
+			"FROM board WHERE owner_id = $1 FOR UPDATE;")
+
+		trashBoardSQL = "UPDATE board SET owner_id = 0, deleted_at = COALESCE(deleted_at, now()) WHERE board_id = $1;"
+
+		removeContributorSQL = "DELETE FROM contributor WHERE person_id = $1 AND board_id = $2;"
+		setOwnerSQL          = "UPDATE board SET owner_id = $1 WHERE board_id = $2;"
+
+		deletePersonSQL = "DELETE FROM person WHERE person_id = $1;"
+	)
+
+	err := inTx(ctx, pm.DB, func(tx pgx.Tx) error {
+		boards, err := queryRows(ctx, tx, func(b *ownedBoard) []any {
+			return []any{&b.ID, &b.IsDeleted, &b.HasContributors}
+		}, ownedBoardsSQL, personID)
+		if err != nil {
+			return err
+		}
+
+		reassigned := 0
+		for _, board := range boards {
+			successorID, ok := successors[board.ID]
+			switch {
+			case ok && successorID == personID:
+				return newError(ErrInvalidInput, "person %d can't be successor of themselves", personID)
+			case ok && board.HasContributors && !board.IsDeleted:
+				if _, err := reassignBoardOwner(ctx, tx, board.ID, successorID); err != nil {
+					return err
+				}
+				reassigned++
+			case ok:
+				if _, err := tx.Exec(ctx, removeContributorSQL, successorID, board.ID); err != nil {
+					return err
+				}
+				if _, err := tx.Exec(ctx, setOwnerSQL, successorID, board.ID); err != nil {
+					return err
+				}
+				reassigned++
+			case board.IsDeleted || !board.HasContributors:
+				if _, err := tx.Exec(ctx, trashBoardSQL, board.ID); err != nil {
+					return err
+				}
+			default:
+				return newError(ErrConflict, "successor of person %d in board %d is not chosen", personID, board.ID)
+			}
+		}
+
+		if reassigned != len(successors) {
+			return newError(ErrInvalidInput, "successors are chosen for boards not owned by person %d", personID)
+		}
+
+		if err := (SessionModel{DB: tx}).RevokeAllByPersonID(ctx, personID); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, deletePersonSQL, personID)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("PersonModel.DeleteByID() -> %w", err)
+		return fmt.Errorf("PersonModel.DeleteByID() -> %w", dbError(err))
 	}
 	return nil
 }
 
-// GetByID - searching for person in DB by id, returning finded Person.
-func (pm PersonModel) GetByID(ctx context.Context, personID uint32) (Person, error) {
-	sql := "SELECT * FROM person WHERE person_id = $1;"
-
-	var obtainedPerson Person
-	err := pm.DB.QueryRow(ctx, sql, personID).Scan(
-		&obtainedPerson.ID,
-		&obtainedPerson.Username,
-		&obtainedPerson.FirstName,
-		&obtainedPerson.LastName,
-		&obtainedPerson.Email,
-		&obtainedPerson.PasswordHash,
-	)
+// UpdatePasswordHash - replaces password hash of person and revokes all sessions of person,
+// so sessions started with old password can't be used anymore.
+func (pm PersonModel) UpdatePasswordHash(ctx context.Context, personID uint32, passwordHash string) error {
+	sql := "UPDATE person SET password_hash = $2 WHERE person_id = $1 RETURNING person_id;"
 
+	err := inTx(ctx, pm.DB, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, sql, personID, passwordHash).Scan(&personID); err != nil {
+			return err
+		}
+		return SessionModel{DB: tx}.RevokeAllByPersonID(ctx, personID)
+	})
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.GetByID() -> %w", err)
+		return fmt.Errorf("PersonModel.UpdatePasswordHash() -> %w", dbError(err))
 	}
+	return nil
+}
+
+// Update - updates row in table 'person' with non-nil fields of `update`.
+// Returning updated Person.
+func (pm PersonModel) Update(ctx context.Context, personID uint32, update PersonUpdate) (Person, error) {
+	sql := ("UPDATE person SET " +
+		"username = COALESCE($2, username), " +
+		"first_name = COALESCE($3, first_name), " +
+		"last_name = COALESCE($4, last_name), " +
+		"email = COALESCE($5, email) " +
+		"WHERE person_id = $1 RETURNING person_id;")
+
+	err := pm.DB.QueryRow(ctx, sql, personID,
+		update.Username,
+		update.FirstName,
+		update.LastName,
+		update.Email,
+	).Scan(&personID)
 
-	obtainedPerson, err = pm.loadEverything(ctx, obtainedPerson)
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.GetByID() -> %w", err)
+		return Person{}, fmt.Errorf("PersonModel.Update() -> %w", dbError(err))
 	}
 
-	return obtainedPerson, nil
+	updatedPerson, err := pm.GetByID(ctx, personID)
+	if err != nil {
+		return Person{}, fmt.Errorf("PersonModel.Update() -> %w", dbError(err))
+	}
+	return updatedPerson, nil
 }
 
-// GetByEmail - searching for person in DB by email, returning finded Person.
-func (pm PersonModel) GetByEmail(ctx context.Context, email string) (Person, error) {
-	sql := "SELECT * FROM person WHERE email = $1;"
-
-	var obtainedPerson Person
-	err := pm.DB.QueryRow(ctx, sql, email).Scan(
-		&obtainedPerson.ID,
-		&obtainedPerson.Username,
-		&obtainedPerson.FirstName,
-		&obtainedPerson.LastName,
-		&obtainedPerson.Email,
-		&obtainedPerson.PasswordHash,
-	)
+// GetByID - searching for person in DB by id, returning finded Person.
+func (pm PersonModel) GetByID(ctx context.Context, personID uint32, opts ...LoadOption) (Person, error) {
+	sql := "SELECT " + personColumns + " FROM person WHERE person_id = $1;"
 
+	obtainedPerson, err := queryRow(ctx, pm.DB, personFields, sql, personID)
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.GetByEmail() -> %w", err)
+		return Person{}, fmt.Errorf("PersonModel.GetByID() -> %w", dbError(err))
 	}
 
-	obtainedPerson, err = pm.loadEverything(ctx, obtainedPerson)
+	obtainedPerson, err = pm.loadEverything(ctx, obtainedPerson, newLoadOptions(opts))
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.GetByEmail() -> %w", err)
+		return Person{}, fmt.Errorf("PersonModel.GetByID() -> %w", dbError(err))
 	}
 
 	return obtainedPerson, nil
 }
 
-// GetByUsername - searching for person in DB by username, returning finded Person.
-func (pm PersonModel) GetByUsername(ctx context.Context, username string) (Person, error) {
-	sql := "SELECT * FROM person WHERE username = $1;"
-
-	var obtainedPerson Person
-	err := pm.DB.QueryRow(ctx, sql, username).Scan(
-		&obtainedPerson.ID,
-		&obtainedPerson.Username,
-		&obtainedPerson.FirstName,
-		&obtainedPerson.LastName,
-		&obtainedPerson.Email,
-		&obtainedPerson.PasswordHash,
-	)
+// GetSmallByID - searching for person in DB by ID without boards and assigned tasks, returning finded SmallPerson.
+func (pm PersonModel) GetSmallByID(ctx context.Context, personID uint32) (SmallPerson, error) {
+	person, err := pm.GetByID(ctx, personID, WithoutRelations())
+	if err != nil {
+		return SmallPerson{}, fmt.Errorf("PersonModel.GetSmallByID() -> %w", dbError(err))
+	}
+	return person.Small(), nil
+}
+
+// GetByEmail - searching for person in DB by email, returning finded Person.
+func (pm PersonModel) GetByEmail(ctx context.Context, email string, opts ...LoadOption) (Person, error) {
+	sql := "SELECT " + personColumns + " FROM person WHERE email = $1;"
+
+	obtainedPerson, err := queryRow(ctx, pm.DB, personFields, sql, email)
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.GetByUsername() -> %w", err)
+		return Person{}, fmt.Errorf("PersonModel.GetByEmail() -> %w", dbError(err))
 	}
 
-	obtainedPerson, err = pm.loadEverything(ctx, obtainedPerson)
+	obtainedPerson, err = pm.loadEverything(ctx, obtainedPerson, newLoadOptions(opts))
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.GetByUsername() -> %w", err)
+		return Person{}, fmt.Errorf("PersonModel.GetByEmail() -> %w", dbError(err))
 	}
 
 	return obtainedPerson, nil
 }
 
-// loadEverything - combines loadAssignedTasks, loadBoards  in one method.
-func (pm PersonModel) loadEverything(ctx context.Context, person Person) (Person, error) {
-	person, err := pm.loadAssignedTasks(ctx, person)
+// GetByUsername - searching for person in DB by username, returning finded Person.
+func (pm PersonModel) GetByUsername(ctx context.Context, username string, opts ...LoadOption) (Person, error) {
+	sql := "SELECT " + personColumns + " FROM person WHERE username = $1;"
+
+	obtainedPerson, err := queryRow(ctx, pm.DB, personFields, sql, username)
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.loadEverything() -> %w", err)
+		return Person{}, fmt.Errorf("PersonModel.GetByUsername() -> %w", dbError(err))
 	}
 
-	person, err = pm.loadBoards(ctx, person)
+	obtainedPerson, err = pm.loadEverything(ctx, obtainedPerson, newLoadOptions(opts))
 	if err != nil {
-		return Person{}, fmt.Errorf("PersonModel.loadEverything() -> %w", err)
+		return Person{}, fmt.Errorf("PersonModel.GetByUsername() -> %w", dbError(err))
 	}
 
-	return person, nil
+	return obtainedPerson, nil
 }
 
-// loadAssignedTasks - loading assigned to person tasks in Person.AssignedTasks slice.
-func (pm PersonModel) loadAssignedTasks(ctx context.Context, person Person) (Person, error) {
-	sql := "SELECT ref_task_id FROM assignee WHERE assignee_id = $1"
-
-	rows, _ := pm.DB.Query(ctx, sql, person.ID)
-
-	localTaskModel := TaskModel(pm)
-	defer rows.Close()
-	var assignedTasks []Task
-	for rows.Next() {
-		var taskID uint32
-		err := rows.Scan(&taskID)
-		if err != nil {
-			return Person{}, fmt.Errorf("PersonModel.loadAssignedTasks() -> %w", err)
+// loadEverything - combines loadAssignedTasks, loadBoards in one method, loading only data selected by o.
+func (pm PersonModel) loadEverything(ctx context.Context, person Person, o loadOptions) (Person, error) {
+	var err error
+	if o.assignedTasks {
+		if person, err = pm.loadAssignedTasks(ctx, person, o); err != nil {
+			return Person{}, fmt.Errorf("PersonModel.loadEverything() -> %w", dbError(err))
 		}
+	}
 
-		task, err := localTaskModel.GetByID(ctx, taskID)
-		if err != nil {
-			return Person{}, fmt.Errorf("PersonModel.loadAssignedTasks() -> %w", err)
+	if o.boards {
+		if person, err = pm.loadBoards(ctx, person); err != nil {
+			return Person{}, fmt.Errorf("PersonModel.loadEverything() -> %w", dbError(err))
 		}
-
-		assignedTasks = append(assignedTasks, task)
 	}
 
-	if err := rows.Err(); err != nil {
-		return Person{}, fmt.Errorf("PersonModel.loadAssignedTasks() -> %w", err)
+	return person, nil
+}
+
+// loadAssignedTasks - loading assigned to person tasks in Person.AssignedTasks slice, tasks archived
+// themselves or together with their board are loaded only if o.archived is set.
+func (pm PersonModel) loadAssignedTasks(ctx context.Context, person Person, o loadOptions) (Person, error) {
+	clauses := ("JOIN assignee ON assignee.ref_task_id = task.task_id " +
+		"WHERE assignee.assignee_id = $1 AND " + visibleTasksSQL + " AND ($2 OR " + unarchivedTasksSQL + ") " +
+		"ORDER BY task.task_id")
+
+	assignedTasks, err := TaskModel(pm).getMany(ctx, o, clauses, person.ID, o.archived)
+	if err != nil {
+		return Person{}, fmt.Errorf("PersonModel.loadAssignedTasks() -> %w", dbError(err))
 	}
 
 	person.AssignedTasks = assignedTasks
 	return person, nil
 }
 
-// loadBoards - loads owned and contributed by person, boards.
+// loadBoards - loads owned and contributed by person, active boards in Person.Boards
+// and archived ones in Person.ArchivedBoards.
 func (pm PersonModel) loadBoards(ctx context.Context, person Person) (Person, error) {
-	sql := ("SELECT board.*, username, first_name, last_name, email " +
-		"FROM board JOIN person ON person_id = owner_id " +
-		"WHERE owner_id = $1 " +
+	sql := ("SELECT " + smallBoardColumns + " " +
+		"FROM board JOIN person ON person.person_id = board.owner_id " +
+		"WHERE board.owner_id = $1 AND board.deleted_at IS NULL AND (board.archived_at IS NOT NULL) = $2 " +
 		"UNION " +
-		"SELECT board.*, username, first_name, last_name, email " +
+		"SELECT " + smallBoardColumns + " " +
 		"FROM contributor " +
 		"JOIN board ON contributor.board_id = board.board_id " +
 		"JOIN person ON board.owner_id = person.person_id " +
-		"WHERE contributor.person_id = $1")
-
-	rows, _ := pm.DB.Query(ctx, sql, person.ID)
-	var boards []SmallBoard
-	for rows.Next() {
-		var board SmallBoard
-		err := rows.Scan(&board.ID, &board.Name,
-			&board.Owner.ID, &board.Owner.Username, &board.Owner.FirstName,
-			&board.Owner.LastName, &board.Owner.Email)
-
-		if err != nil {
-			return Person{}, fmt.Errorf("PersonModel.loadBoards() -> %w", err)
-		}
+		"WHERE contributor.person_id = $1 AND board.deleted_at IS NULL AND (board.archived_at IS NOT NULL) = $2")
 
-		boards = append(boards, board)
+	boards, err := queryRows(ctx, pm.DB, smallBoardFields, sql, person.ID, false)
+	if err != nil {
+		return Person{}, fmt.Errorf("PersonModel.loadBoards() -> %w", dbError(err))
 	}
 
-	if err := rows.Err(); err != nil {
-		return Person{}, fmt.Errorf("PersonModel.loadBoards() -> %w", err)
+	archivedBoards, err := queryRows(ctx, pm.DB, smallBoardFields, sql, person.ID, true)
+	if err != nil {
+		return Person{}, fmt.Errorf("PersonModel.loadBoards() -> %w", dbError(err))
 	}
 
 	person.Boards = boards
+	person.ArchivedBoards = archivedBoards
 	return person, nil
 }
@@ -0,0 +1,351 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TaskQuery - parsed filter of board tasks, see ParseTaskQuery. Zero TaskQuery matches all tasks.
+type TaskQuery struct {
+	conditions []queryCondition
+}
+
+// QueryError - error of parsing TaskQuery, reports offending token of query. QueryError is of ErrInvalidInput kind.
+type QueryError struct {
+	Token   string
+	Message string
+	Pos     int // byte offset of token in query
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s: %q at position %d", e.Message, e.Token, e.Pos)
+}
+
+// Is - reports if target is ErrInvalidInput.
+func (e *QueryError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// queryCondition - compiles term of TaskQuery to SQL condition on task table, adding its values to args.
+type queryCondition func(args *queryArgs) string
+
+// queryArgs - args of query, which conditions of TaskQuery are compiled into.
+type queryArgs []any
+
+// add - appends v to args, returns placeholder of v.
+func (a *queryArgs) add(v any) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// queryTerm - term of TaskQuery, like '-tag:blocked' or 'due:<7d'.
+type queryTerm struct {
+	token   string // whole term, as written in query
+	key     string // empty for plain word
+	op      string // one of ':', '<', '<=', '>', '>='
+	value   string // unquoted
+	pos     int
+	negated bool
+}
+
+// errorf - returns QueryError about t with message formatted by format and args.
+func (t queryTerm) errorf(format string, args ...any) error {
+	return &QueryError{Token: t.token, Message: fmt.Sprintf(format, args...), Pos: t.pos}
+}
+
+// queryKey - key of TaskQuery terms.
+type queryKey struct {
+	parse      func(queryTerm) (queryCondition, error)
+	comparable bool // supports comparisons <, <=, > and >=
+}
+
+// queryKeys - keys of TaskQuery terms by their names.
+var queryKeys = map[string]queryKey{
+	"assignee": {parse: parseAssigneeTerm},
+	"author":   {parse: parseAuthorTerm},
+	"tag":      {parse: parseTagTerm},
+	"column":   {parse: parseColumnTerm},
+	"priority": {parse: parsePriorityTerm, comparable: true},
+	"estimate": {parse: parseEstimateTerm, comparable: true},
+	"due":      {parse: parseDateTerm("task.due_at"), comparable: true},
+	"start":    {parse: parseDateTerm("task.start_at"), comparable: true},
+}
+
+// ParseTaskQuery - parses filter of tasks. Query is list of terms separated by spaces, task matches query
+// if it matches all its terms. Term is 'key:value' or plain word, which is searched in task name and
+// description, term prefixed with '-' matches tasks not matching term itself. Values with spaces are
+// written in double quotes, e.g. 'tag:"needs review"'. Supported keys are:
+//   - assignee - username of person or 'none' for tasks without assignees;
+//   - author - username of person;
+//   - tag, column - name of tag or column, case insensitive, 'tag:none' matches tasks without tags;
+//   - priority - one of TaskPriorities, compared by their order, e.g. 'priority:>=high';
+//   - estimate - number or 'none', e.g. 'estimate:<=3';
+//   - due, start - date in 2006-01-02 format (UTC), time relative to now in hours, days or weeks,
+//     e.g. 'due:<7d' or 'due:>-2w', or 'none'. Relative time must be compared by <, <=, > or >=.
+//
+// Comparisons <, <=, > and >= are written after colon and are supported by priority, estimate, due and start.
+func ParseTaskQuery(query string) (TaskQuery, error) {
+	terms, err := splitQuery(query)
+	if err != nil {
+		return TaskQuery{}, err
+	}
+
+	var q TaskQuery
+	for _, term := range terms {
+		condition, termErr := parseTerm(term)
+		if termErr != nil {
+			return TaskQuery{}, termErr
+		}
+
+		if term.negated {
+			positive := condition
+			condition = func(args *queryArgs) string { return "(" + positive(args) + ") IS NOT TRUE" }
+		}
+		q.conditions = append(q.conditions, condition)
+	}
+	return q, nil
+}
+
+// sql - returns condition selecting tasks matching q, adding its values to args.
+func (q TaskQuery) sql(args *queryArgs) string {
+	if len(q.conditions) == 0 {
+		return "TRUE"
+	}
+
+	conditions := make([]string, len(q.conditions))
+	for i, condition := range q.conditions {
+		conditions[i] = condition(args)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// splitQuery - splits query to terms by spaces outside of double quotes.
+func splitQuery(query string) ([]queryTerm, error) {
+	var terms []queryTerm
+	start, quoted := -1, false
+	for i, r := range query + " " {
+		switch {
+		case start == -1 && unicode.IsSpace(r):
+		case start == -1:
+			start, quoted = i, r == '"'
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			term, err := newQueryTerm(query[start:i], start)
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, term)
+			start = -1
+		}
+	}
+
+	if start != -1 {
+		return nil, &QueryError{Token: query[start:], Message: "unterminated quote", Pos: start}
+	}
+	return terms, nil
+}
+
+// newQueryTerm - splits token at pos of query to parts of queryTerm.
+func newQueryTerm(token string, pos int) (queryTerm, error) {
+	term := queryTerm{token: token, pos: pos}
+	rest := token
+	if len(rest) > 1 && rest[0] == '-' {
+		term.negated, rest = true, rest[1:]
+	}
+
+	if i := strings.IndexByte(rest, ':'); i > 0 && !strings.HasPrefix(rest, "\"") {
+		term.key, rest = strings.ToLower(rest[:i]), rest[i+1:]
+		term.op = ":"
+		for _, op := range []string{"<=", ">=", "<", ">"} {
+			if strings.HasPrefix(rest, op) {
+				term.op, rest = op, rest[len(op):]
+				break
+			}
+		}
+	}
+
+	if len(rest) >= 2 && rest[0] == '"' && rest[len(rest)-1] == '"' {
+		rest = rest[1 : len(rest)-1]
+	}
+	if strings.Contains(rest, "\"") {
+		return queryTerm{}, term.errorf("unexpected quote")
+	}
+	if rest == "" {
+		return queryTerm{}, term.errorf("empty value")
+	}
+
+	term.value = rest
+	return term, nil
+}
+
+// parseTerm - returns condition of term.
+func parseTerm(term queryTerm) (queryCondition, error) {
+	if term.key == "" {
+		return func(args *queryArgs) string {
+			word := args.add(term.value)
+			return fmt.Sprintf("(strpos(lower(task.task_name), lower(%[1]s::VARCHAR)) > 0 OR "+
+				"strpos(lower(task.task_description), lower(%[1]s::VARCHAR)) > 0)", word)
+		}, nil
+	}
+
+	key, ok := queryKeys[term.key]
+	if !ok {
+		return nil, term.errorf("unknown key %q", term.key)
+	}
+	if term.op != ":" && !key.comparable {
+		return nil, term.errorf("key %q doesn't support comparison", term.key)
+	}
+	return key.parse(term)
+}
+
+// parseAssigneeTerm - parses 'assignee:username' term.
+func parseAssigneeTerm(term queryTerm) (queryCondition, error) {
+	if term.value == "none" {
+		return func(args *queryArgs) string {
+			return "NOT EXISTS (SELECT 1 FROM assignee WHERE assignee.ref_task_id = task.task_id)"
+		}, nil
+	}
+
+	return func(args *queryArgs) string {
+		return ("EXISTS (SELECT 1 FROM assignee " +
+			"JOIN person AS assignee_person ON assignee_person.person_id = assignee.assignee_id " +
+			"WHERE assignee.ref_task_id = task.task_id AND assignee_person.username = " +
+			args.add(term.value) + "::VARCHAR)")
+	}, nil
+}
+
+// parseAuthorTerm - parses 'author:username' term.
+func parseAuthorTerm(term queryTerm) (queryCondition, error) {
+	return func(args *queryArgs) string {
+		return ("EXISTS (SELECT 1 FROM person AS author WHERE author.person_id = task.author_id " +
+			"AND author.username = " + args.add(term.value) + "::VARCHAR)")
+	}, nil
+}
+
+// parseTagTerm - parses 'tag:name' term.
+func parseTagTerm(term queryTerm) (queryCondition, error) {
+	if term.value == "none" {
+		return func(args *queryArgs) string {
+			return "NOT EXISTS (SELECT 1 FROM task_tag WHERE task_tag.ref_task_id = task.task_id)"
+		}, nil
+	}
+
+	return func(args *queryArgs) string {
+		return ("EXISTS (SELECT 1 FROM task_tag JOIN tag ON tag.tag_id = task_tag.ref_tag_id " +
+			"WHERE task_tag.ref_task_id = task.task_id AND lower(tag.tag_name) = lower(" +
+			args.add(term.value) + "::VARCHAR))")
+	}, nil
+}
+
+// parseColumnTerm - parses 'column:name' term.
+func parseColumnTerm(term queryTerm) (queryCondition, error) {
+	return func(args *queryArgs) string {
+		return ("EXISTS (SELECT 1 FROM \"column\" AS task_column " +
+			"WHERE task_column.column_id = task.column_id AND lower(task_column.column_name) = lower(" +
+			args.add(term.value) + "::VARCHAR))")
+	}, nil
+}
+
+// parsePriorityTerm - parses 'priority:high' term, priorities are compared by their order in TaskPriorities.
+func parsePriorityTerm(term queryTerm) (queryCondition, error) {
+	rank := 0
+	for i, priority := range TaskPriorities {
+		if string(priority) == strings.ToLower(term.value) {
+			rank = i + 1
+		}
+	}
+	if rank == 0 {
+		return nil, term.errorf("unknown priority, expected one of %v", TaskPriorities)
+	}
+
+	return func(args *queryArgs) string {
+		return fmt.Sprintf("array_position(%s::VARCHAR[], task.task_priority) %s %s",
+			args.add(priorityStrings(TaskPriorities)), sqlOperator(term.op), args.add(rank))
+	}, nil
+}
+
+// parseEstimateTerm - parses 'estimate:3' term.
+func parseEstimateTerm(term queryTerm) (queryCondition, error) {
+	if term.value == "none" {
+		return parseNoneTerm(term, "task.estimate")
+	}
+
+	estimate, err := strconv.ParseFloat(term.value, 64)
+	if err != nil {
+		return nil, term.errorf("invalid estimate, expected number")
+	}
+
+	return func(args *queryArgs) string {
+		return fmt.Sprintf("task.estimate %s %s::DOUBLE PRECISION", sqlOperator(term.op), args.add(estimate))
+	}, nil
+}
+
+// relativeUnits - units of relative time in date terms.
+var relativeUnits = map[byte]string{'h': "hours", 'd': "days", 'w': "weeks"}
+
+// parseDateTerm - returns parser of date terms, like 'due:2006-01-02' or 'due:<7d', of column.
+func parseDateTerm(column string) func(queryTerm) (queryCondition, error) {
+	return func(term queryTerm) (queryCondition, error) {
+		if term.value == "none" {
+			return parseNoneTerm(term, column)
+		}
+
+		if day, err := time.Parse("2006-01-02", term.value); err == nil {
+			return dayCondition(column, term.op, day), nil
+		}
+
+		unit, ok := relativeUnits[term.value[len(term.value)-1]]
+		count, err := strconv.Atoi(term.value[:len(term.value)-1])
+		if !ok || err != nil {
+			return nil, term.errorf("invalid date, expected 2006-01-02, relative time like 7d or none")
+		}
+		if term.op == ":" {
+			return nil, term.errorf("relative time must be compared, e.g. %s:<%s", term.key, term.value)
+		}
+
+		interval := fmt.Sprintf("%d %s", count, unit)
+		return func(args *queryArgs) string {
+			return fmt.Sprintf("%s %s now() + %s::INTERVAL", column, term.op, args.add(interval))
+		}, nil
+	}
+}
+
+// dayCondition - returns condition comparing column with day, day is matched as whole, e.g. '<=' includes it.
+func dayCondition(column, op string, day time.Time) queryCondition {
+	next := day.AddDate(0, 0, 1)
+	return func(args *queryArgs) string {
+		switch op {
+		case "<":
+			return fmt.Sprintf("%s < %s::TIMESTAMPTZ", column, args.add(day))
+		case "<=":
+			return fmt.Sprintf("%s < %s::TIMESTAMPTZ", column, args.add(next))
+		case ">":
+			return fmt.Sprintf("%s >= %s::TIMESTAMPTZ", column, args.add(next))
+		case ">=":
+			return fmt.Sprintf("%s >= %s::TIMESTAMPTZ", column, args.add(day))
+		default:
+			return fmt.Sprintf("(%[1]s >= %[2]s::TIMESTAMPTZ AND %[1]s < %[3]s::TIMESTAMPTZ)",
+				column, args.add(day), args.add(next))
+		}
+	}
+}
+
+// parseNoneTerm - parses 'key:none' term matching tasks with NULL column.
+func parseNoneTerm(term queryTerm, column string) (queryCondition, error) {
+	if term.op != ":" {
+		return nil, term.errorf("none can't be compared")
+	}
+	return func(args *queryArgs) string { return column + " IS NULL" }, nil
+}
+
+// sqlOperator - returns SQL operator of comparison op of term.
+func sqlOperator(op string) string {
+	if op == ":" {
+		return "="
+	}
+	return op
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+)
+
+// Every model has columns constant with explicit list of selected columns and fields function,
+// that returns destinations for scanning row selected by these columns in the same order.
+// Adding column to table doesn't break queries, until it is added to columns constant.
+
+// queryRow - runs query, that returns one row, and scans it by fields.
+// Returns pgx.ErrNoRows if query returned no rows.
+func queryRow[T any](ctx context.Context, dbConn DBConn, fields func(*T) []any,
+	sql string, args ...any) (T, error) {
+	var v T
+	err := dbConn.QueryRow(ctx, sql, args...).Scan(fields(&v)...)
+	return v, err
+}
+
+// queryRows - runs query and scans all returned rows by fields, returns nil slice if no rows returned.
+// Error of query is returned too, so broken connection can't be confused with empty result.
+func queryRows[T any](ctx context.Context, dbConn DBConn, fields func(*T) []any,
+	sql string, args ...any) ([]T, error) {
+	rows, err := dbConn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []T
+	for rows.Next() {
+		var v T
+		if err := rows.Scan(fields(&v)...); err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// taskRelation - row of data related to task, like tag or assignee of task.
+type taskRelation[T any] struct {
+	value  T
+	taskID uint32
+}
+
+// taskRelationFields - returns fields function for rows selected by 'ref_task_id' followed by columns of T.
+func taskRelationFields[T any](fields func(*T) []any) func(*taskRelation[T]) []any {
+	return func(r *taskRelation[T]) []any {
+		return append([]any{&r.taskID}, fields(&r.value)...)
+	}
+}
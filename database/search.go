@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SearchQuery - conditions of SearchModel.Search, zero filters aren't checked.
+type SearchQuery struct {
+	Text            string // in web search syntax: words, "quoted phrases", 'or' and -excluded words
+	Limit           int    // DefaultPageLimit if not set, can't be greater than MaxPageLimit
+	Offset          int    // count of skipped results
+	PersonID        uint32 // required, only tasks of boards owned or contributed by person are searched
+	BoardID         uint32
+	TagID           uint32 // task has tag
+	AssigneeID      uint32 // task is assigned to person
+	AuthorID        uint32 // task is created by person
+	IncludeArchived bool   // archived tasks and tasks of archived boards are searched too
+}
+
+// SearchResult - task found by SearchModel.Search.
+type SearchResult struct {
+	Highlights SearchHighlights `json:"highlights"`
+	Task       Task             `json:"task"`
+	Rank       float32          `json:"rank"` // relevance of task, results are ordered by it
+}
+
+// SearchHighlights - texts of found task with matched words wrapped in <b></b>.
+type SearchHighlights struct {
+	Name        string   `json:"task_name"`
+	Description string   `json:"task_description"` // up to 2 fragments around matches
+	Subtasks    []string `json:"subtasks"`         // names of matched subtasks
+	Comments    []string `json:"comments"`         // fragments of matched comments
+}
+
+// searchConfig - text search configuration of task documents, must match one in migrations.
+const searchConfig = "'english'"
+
+// searchColumns - ID, rank and highlights of task matched by tsquery 'query.q', scanned by searchFields.
+const searchColumns = ("task.task_id, ts_rank(task_search.document, query.q), " +
+	"ts_headline(" + searchConfig + ", task.task_name, query.q, 'HighlightAll=true'), " +
+	"ts_headline(" + searchConfig + ", COALESCE(task.task_description, ''), query.q, 'MaxFragments=2'), " +
+	"ARRAY(SELECT ts_headline(" + searchConfig + ", subtask.subtask_name, query.q, 'HighlightAll=true') " +
+	"FROM subtask WHERE subtask.parent_task_id = task.task_id " +
+	"AND to_tsvector(" + searchConfig + ", subtask.subtask_name) @@ query.q ORDER BY subtask.subtask_position), " +
+	"ARRAY(SELECT ts_headline(" + searchConfig + ", comment.comment_text, query.q, 'MaxFragments=1') " +
+	"FROM comment WHERE comment.task_id = task.task_id " +
+	"AND to_tsvector(" + searchConfig + ", comment.comment_text) @@ query.q ORDER BY comment.comment_id)")
+
+// searchFields - returns destinations for scanning row selected by searchColumns into r,
+// only ID of r.Task is scanned.
+func searchFields(r *SearchResult) []any {
+	return []any{&r.Task.ID, &r.Rank, &r.Highlights.Name, &r.Highlights.Description,
+		&r.Highlights.Subtasks, &r.Highlights.Comments}
+}
+
+// SearchModel - struct that implements SearchManager interface for full-text search of tasks in db.
+type SearchModel struct {
+	DB DBConn
+}
+
+// Search - returns tasks matching q.Text by name, description, names of subtasks or text of comments,
+// most relevant first. Name matches weigh most, then description, subtasks and comments.
+func (sm SearchModel) Search(ctx context.Context, q SearchQuery) ([]SearchResult, error) {
+	sql, args, err := q.sql()
+	if err != nil {
+		return nil, fmt.Errorf("SearchModel.Search() -> %w", err)
+	}
+
+	results, err := queryRows(ctx, sm.DB, searchFields, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SearchModel.Search() -> %w", dbError(err))
+	}
+
+	ids := make([]uint32, len(results))
+	for i, result := range results {
+		ids[i] = result.Task.ID
+	}
+
+	tasks, err := TaskModel(sm).getMany(ctx, newLoadOptions(nil), "WHERE task.task_id = ANY($1)", ids)
+	if err != nil {
+		return nil, fmt.Errorf("SearchModel.Search() -> %w", dbError(err))
+	}
+
+	index := indexTasks(tasks)
+	for i, result := range results {
+		if j, ok := index[result.Task.ID]; ok {
+			results[i].Task = tasks[j]
+		}
+	}
+	return results, nil
+}
+
+// sql - returns query selecting page of results of q by searchColumns, and its args.
+func (q SearchQuery) sql() (string, []any, error) {
+	if strings.TrimSpace(q.Text) == "" {
+		return "", nil, newError(ErrInvalidInput, "search text is empty")
+	}
+
+	args := []any{q.Text, q.PersonID}
+	sql := ("SELECT " + searchColumns + " " +
+		"FROM websearch_to_tsquery(" + searchConfig + ", $1) AS query(q), task_search " +
+		"JOIN task ON task.task_id = task_search.task_id " +
+		"WHERE task_search.document @@ query.q AND " + visibleTasksSQL + " " +
+		"AND task.board_id IN (SELECT board_id FROM board WHERE owner_id = $2 " +
+		"UNION SELECT board_id FROM contributor WHERE person_id = $2)")
+
+	if !q.IncludeArchived {
+		sql += " AND " + unarchivedTasksSQL
+	}
+
+	if q.BoardID != 0 {
+		args = append(args, q.BoardID)
+		sql += fmt.Sprintf(" AND task.board_id = $%d", len(args))
+	}
+
+	if q.TagID != 0 {
+		args = append(args, q.TagID)
+		sql += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM task_tag "+
+			"WHERE task_tag.ref_task_id = task.task_id AND task_tag.ref_tag_id = $%d)", len(args))
+	}
+
+	if q.AssigneeID != 0 {
+		args = append(args, q.AssigneeID)
+		sql += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM assignee "+
+			"WHERE assignee.ref_task_id = task.task_id AND assignee.assignee_id = $%d)", len(args))
+	}
+
+	if q.AuthorID != 0 {
+		args = append(args, q.AuthorID)
+		sql += fmt.Sprintf(" AND task.author_id = $%d", len(args))
+	}
+
+	args = append(args, Page{Limit: q.Limit}.limit(), q.Offset)
+	sql += fmt.Sprintf(" ORDER BY 2 DESC, task.task_id LIMIT $%d OFFSET $%d;", len(args)-1, len(args))
+	return sql, args, nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SessionKind - kind of session, defines how session token is passed by client.
+type SessionKind string
+
+const (
+	// SessionCookie - browser session, token is passed in signed cookie.
+	SessionCookie SessionKind = "cookie"
+	// SessionToken - API token for scripts, token is passed in 'Authorization: Bearer' header.
+	SessionToken SessionKind = "token"
+)
+
+// Session - session model struct, session is identified by hash of it's token,
+// token itself is never saved in db.
+type Session struct {
+	CreatedAt time.Time   `json:"created_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	RevokedAt *time.Time  `json:"revoked_at"`
+	TokenHash string      `json:"-"`
+	Kind      SessionKind `json:"session_kind"`
+	ID        uint32      `json:"session_id"`
+	PersonID  uint32      `json:"person_id"`
+}
+
+// sessionColumns - columns of session table, scanned by sessionFields.
+const sessionColumns = ("session.session_id, session.token_hash, session.person_id, session.session_kind, " +
+	"session.created_at, session.expires_at, session.revoked_at")
+
+// sessionFields - returns destinations for scanning row selected by sessionColumns into s.
+func sessionFields(s *Session) []any {
+	return []any{&s.ID, &s.TokenHash, &s.PersonID, &s.Kind, &s.CreatedAt, &s.ExpiresAt, &s.RevokedAt}
+}
+
+// SessionModel - struct that implements SessionManager interface for interacting with session table in db.
+type SessionModel struct {
+	DB DBConn
+}
+
+// Create - Creates new row in table 'session'.
+// Returning created Session.
+func (sm SessionModel) Create(ctx context.Context, session Session) (Session, error) {
+	sql := ("INSERT INTO session (token_hash, person_id, session_kind, expires_at) " +
+		"VALUES ($1, $2, $3, $4) " +
+		"RETURNING " + sessionColumns + ";")
+
+	createdSession, err := queryRow(ctx, sm.DB, sessionFields, sql,
+		session.TokenHash,
+		session.PersonID,
+		session.Kind,
+		session.ExpiresAt,
+	)
+
+	if err != nil {
+		return Session{}, fmt.Errorf("SessionModel.Create() -> %w", dbError(err))
+	}
+	return createdSession, nil
+}
+
+// GetActiveByTokenHash - searching for not expired and not revoked session by token hash, returning finded Session.
+func (sm SessionModel) GetActiveByTokenHash(ctx context.Context, tokenHash string) (Session, error) {
+	sql := ("SELECT " + sessionColumns + " " +
+		"FROM session " +
+		"WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > now();")
+
+	obtainedSession, err := queryRow(ctx, sm.DB, sessionFields, sql, tokenHash)
+
+	if err != nil {
+		return Session{}, fmt.Errorf("SessionModel.GetActiveByTokenHash() -> %w", dbError(err))
+	}
+	return obtainedSession, nil
+}
+
+// RevokeByID - marks session as revoked, so it can't be used anymore.
+func (sm SessionModel) RevokeByID(ctx context.Context, sessionID uint32) error {
+	sql := "UPDATE session SET revoked_at = now() WHERE session_id = $1 AND revoked_at IS NULL;"
+	_, err := sm.DB.Exec(ctx, sql, sessionID)
+	if err != nil {
+		return fmt.Errorf("SessionModel.RevokeByID() -> %w", dbError(err))
+	}
+	return nil
+}
+
+// RevokeAllByPersonID - marks all sessions of person as revoked.
+func (sm SessionModel) RevokeAllByPersonID(ctx context.Context, personID uint32) error {
+	sql := "UPDATE session SET revoked_at = now() WHERE person_id = $1 AND revoked_at IS NULL;"
+	_, err := sm.DB.Exec(ctx, sql, personID)
+	if err != nil {
+		return fmt.Errorf("SessionModel.RevokeAllByPersonID() -> %w", dbError(err))
+	}
+	return nil
+}
+
+// DeleteExpired - deletes expired and revoked sessions, returning count of deleted rows.
+func (sm SessionModel) DeleteExpired(ctx context.Context) (int64, error) {
+	sql := "DELETE FROM session WHERE expires_at <= now() OR revoked_at IS NOT NULL;"
+	cmdTag, err := sm.DB.Exec(ctx, sql)
+	if err != nil {
+		return 0, fmt.Errorf("SessionModel.DeleteExpired() -> %w", dbError(err))
+	}
+	return cmdTag.RowsAffected(), nil
+}
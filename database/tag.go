@@ -3,14 +3,33 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // Tag - tag model struct.
 type Tag struct {
-	Name        string `json:"tag_name"`
-	Description string `json:"tag_description"`
-	ID          uint32 `json:"tag_id"`
-	BoardID     uint32 `json:"board_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"tag_name"`
+	Description string    `json:"tag_description"`
+	ID          uint32    `json:"tag_id"`
+	BoardID     uint32    `json:"board_id"`
+}
+
+// tagColumns - columns of tag table, scanned by tagFields.
+const tagColumns = "tag.tag_id, tag.tag_name, tag.tag_description, tag.board_id, tag.created_at, tag.updated_at"
+
+// tagFields - returns destinations for scanning row selected by tagColumns into t.
+func tagFields(t *Tag) []any {
+	return []any{&t.ID, &t.Name, &t.Description, &t.BoardID, &t.CreatedAt, &t.UpdatedAt}
+}
+
+// TagUpdate - fields to change in TagModel.Update, nil fields are left unchanged.
+type TagUpdate struct {
+	Name        *string `json:"tag_name"`
+	Description *string `json:"tag_description"`
 }
 
 // TagModel - struct that implements TagManager interface for interacting with tag table in db.
@@ -25,23 +44,22 @@ type TagModel struct {
 func (tm TagModel) Create(ctx context.Context, tag Tag) (Tag, error) {
 	sql := ("INSERT INTO " +
 		"tag (tag_name, tag_description, board_id) " +
-		"VALUES ($1, $2, $3)" +
-		"RETURNING *;")
+		"VALUES ($1, $2, $3) " +
+		"RETURNING " + tagColumns + ";")
 
 	var createdTag Tag
-	err := tm.DB.QueryRow(ctx, sql,
-		tag.Name,
-		tag.Description,
-		tag.BoardID,
-	).Scan(
-		&createdTag.ID,
-		&createdTag.Name,
-		&createdTag.Description,
-		&createdTag.BoardID,
-	)
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		var err error
+		createdTag, err = queryRow(ctx, tx, tagFields, sql,
+			tag.Name,
+			tag.Description,
+			tag.BoardID,
+		)
+		return err
+	})
 
 	if err != nil {
-		return Tag{}, fmt.Errorf("TagModel.Create() -> %w", err)
+		return Tag{}, fmt.Errorf("TagModel.Create() -> %w", dbError(err))
 	}
 	return createdTag, nil
 }
@@ -49,27 +67,46 @@ func (tm TagModel) Create(ctx context.Context, tag Tag) (Tag, error) {
 // DeleteByID - deletes row from table 'tag'.
 func (tm TagModel) DeleteByID(ctx context.Context, tagID uint32) error {
 	sql := "DELETE FROM tag WHERE tag_id = $1;"
-	_, err := tm.DB.Exec(ctx, sql, tagID)
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, sql, tagID)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("TagModel.DeleteByID() -> %w", err)
+		return fmt.Errorf("TagModel.DeleteByID() -> %w", dbError(err))
 	}
 	return nil
 }
 
+// Update - updates row in table 'tag' with non-nil fields of `update`.
+// Returning updated Tag.
+func (tm TagModel) Update(ctx context.Context, tagID uint32, update TagUpdate) (Tag, error) {
+	sql := ("UPDATE tag SET " +
+		"tag_name = COALESCE($2, tag_name), " +
+		"tag_description = COALESCE($3, tag_description) " +
+		"WHERE tag_id = $1 RETURNING tag_id;")
+
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, tagID, update.Name, update.Description).Scan(&tagID)
+	})
+	if err != nil {
+		return Tag{}, fmt.Errorf("TagModel.Update() -> %w", dbError(err))
+	}
+
+	updatedTag, err := tm.GetByID(ctx, tagID)
+	if err != nil {
+		return Tag{}, fmt.Errorf("TagModel.Update() -> %w", dbError(err))
+	}
+	return updatedTag, nil
+}
+
 // GetByID - searching for tag in DB by ID, returning finded Tag.
 func (tm TagModel) GetByID(ctx context.Context, tagID uint32) (Tag, error) {
-	sql := "SELECT * FROM tag WHERE tag_id = $1;"
+	sql := "SELECT " + tagColumns + " FROM tag WHERE tag_id = $1;"
 
-	var obtainedTag Tag
-	err := tm.DB.QueryRow(ctx, sql, tagID).Scan(
-		&obtainedTag.ID,
-		&obtainedTag.Name,
-		&obtainedTag.Description,
-		&obtainedTag.BoardID,
-	)
+	obtainedTag, err := queryRow(ctx, tm.DB, tagFields, sql, tagID)
 
 	if err != nil {
-		return Tag{}, fmt.Errorf("BoardModel.GetByID() -> %w", err)
+		return Tag{}, fmt.Errorf("BoardModel.GetByID() -> %w", dbError(err))
 	}
 	return obtainedTag, nil
 }
@@ -3,25 +3,121 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// positionGap - distance between positions of neighbour tasks in column after creation or
+// rebalancing, gaps allow to move task between neighbours without renumbering whole column.
+const positionGap = 1 << 16
+
 // Task - task model struct.
 type Task struct {
-	Assignees   []TaskAssignee
-	Author      TaskAuthor `json:"author"`
-	Name        string     `json:"task_name"`
-	Description string     `json:"task_description"`
-	Subtasks    []Subtask
-	Tags        []Tag
-	ID          uint32 `json:"task_id"`
-	BoardID     uint32 `json:"board_id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Assignees      []TaskAssignee `json:"assignees"`
+	Author         TaskAuthor     `json:"author"`
+	Name           string         `json:"task_name"`
+	Description    string         `json:"task_description"`
+	StartAt        *time.Time     `json:"start_at"`         // optional, when work on task is planned to start
+	DueAt          *time.Time     `json:"due_at"`           // optional, deadline of task
+	DeletedAt      *time.Time     `json:"deleted_at"`       // set if task is in trash
+	ArchivedAt     *time.Time     `json:"archived_at"`      // set if task is archived
+	LastModifiedBy *uint32        `json:"last_modified_by"` // person who last changed task, nil if unknown
+	Priority       TaskPriority   `json:"priority"`
+	Estimate       *float64       `json:"estimate"` // optional, in story points
+	Subtasks       []Subtask      `json:"subtasks"`
+	Tags           []Tag          `json:"tags"`
+	Position       int64          `json:"task_position"`
+	Progress       TaskProgress   `json:"progress"` // counted by subtasks, even if Subtasks aren't loaded
+	ID             uint32         `json:"task_id"`
+	BoardID        uint32         `json:"board_id"`
+	ColumnID       uint32         `json:"column_id"`
 }
 
-// Subtask - subtask model struct.
+// Subtask - subtask model struct, subtasks are checklist of their parent task.
 type Subtask struct {
-	Name         string `json:"subtask_name"`
-	ID           uint32 `json:"subtask_id"`
-	ParentTaskID uint32 `json:"parent_task_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AssigneeID   *uint32   `json:"assignee_id"` // optional, person responsible for subtask
+	Name         string    `json:"subtask_name"`
+	ID           uint32    `json:"subtask_id"`
+	ParentTaskID uint32    `json:"parent_task_id"`
+	Position     uint32    `json:"subtask_position"` // one-based index in checklist of parent task
+	IsDone       bool      `json:"is_done"`
+}
+
+// SubtaskUpdate - fields to change in TaskModel.UpdateSubtask, nil fields are left unchanged.
+// Assignee is removed from subtask by ClearAssignee, AssigneeID is ignored then.
+type SubtaskUpdate struct {
+	Name          *string `json:"subtask_name"`
+	IsDone        *bool   `json:"is_done"`
+	AssigneeID    *uint32 `json:"assignee_id"`
+	ClearAssignee bool    `json:"clear_assignee"`
+}
+
+// TaskProgress - progress of task checklist, e.g. 3 of 5 subtasks are done.
+type TaskProgress struct {
+	Done  uint32 `json:"done"`
+	Total uint32 `json:"total"`
+}
+
+// TaskPriority - priority of task, one of TaskPriorities.
+type TaskPriority string
+
+const (
+	// PriorityNone - priority of task isn't set, default priority.
+	PriorityNone TaskPriority = "none"
+	// PriorityLow - task can wait.
+	PriorityLow TaskPriority = "low"
+	// PriorityMedium - task should be done in usual order.
+	PriorityMedium TaskPriority = "medium"
+	// PriorityHigh - task should be done before others.
+	PriorityHigh TaskPriority = "high"
+	// PriorityUrgent - task must be done as soon as possible.
+	PriorityUrgent TaskPriority = "urgent"
+)
+
+// TaskPriorities - all priorities of task ordered from lowest to highest.
+var TaskPriorities = []TaskPriority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// TaskUpdate - fields to change in TaskModel.Update, nil fields are left unchanged.
+// Dates are removed from task by ClearStartAt and ClearDueAt, StartAt and DueAt are ignored then,
+// the same way estimate is removed by ClearEstimate.
+type TaskUpdate struct {
+	Name          *string       `json:"task_name"`
+	Description   *string       `json:"task_description"`
+	StartAt       *time.Time    `json:"start_at"`
+	DueAt         *time.Time    `json:"due_at"`
+	Priority      *TaskPriority `json:"priority"`
+	Estimate      *float64      `json:"estimate"`
+	ClearStartAt  bool          `json:"clear_start_at"`
+	ClearDueAt    bool          `json:"clear_due_at"`
+	ClearEstimate bool          `json:"clear_estimate"`
+}
+
+// TaskSort - order of tasks returned by TaskModel.GetByFilter.
+type TaskSort string
+
+const (
+	// SortByPosition - tasks are ordered as on board, column by column, default order.
+	SortByPosition TaskSort = "position"
+	// SortByPriority - tasks with highest priority first.
+	SortByPriority TaskSort = "priority"
+	// SortByEstimate - tasks with smallest estimate first, tasks without estimate last.
+	SortByEstimate TaskSort = "estimate"
+)
+
+// TaskFilter - conditions of TaskModel.GetByFilter, zero fields aren't checked.
+type TaskFilter struct {
+	MinEstimate     *float64 // task has estimate >= MinEstimate
+	MaxEstimate     *float64 // task has estimate <= MaxEstimate
+	Sort            TaskSort
+	Priorities      []TaskPriority // task has one of priorities
+	BoardID         uint32         // required
+	ColumnID        uint32
+	IncludeArchived bool // archived tasks are selected too
 }
 
 // TaskAuthor - other name for SmallPerson struct, used for representing task author in Task struct.
@@ -30,102 +126,422 @@ type TaskAuthor SmallPerson
 // TaskAssignee - other name for SmallPerson struct, used for representing task executor in Task struct.
 type TaskAssignee SmallPerson
 
+// taskColumns - columns of task table joined with author from person table, scanned by taskFields.
+// Description is NULL in tasks created before migrations.
+const taskColumns = ("task.task_id, task.task_name, COALESCE(task.task_description, ''), task.board_id, " +
+	"task.column_id, task.task_position, task.start_at, task.due_at, " +
+	"task.created_at, task.updated_at, task.last_modified_by, task.task_priority, task.estimate, " +
+	"task.deleted_at, task.archived_at, " + taskProgressColumns + ", " + smallPersonColumns)
+
+// taskProgressColumns - done and total counts of task subtasks, scanned into TaskProgress.
+const taskProgressColumns = ("" +
+	"(SELECT COUNT(*) FILTER (WHERE subtask.is_done) FROM subtask " +
+	"WHERE subtask.parent_task_id = task.task_id)::INTEGER, " +
+	"(SELECT COUNT(*) FROM subtask WHERE subtask.parent_task_id = task.task_id)::INTEGER")
+
+// taskFields - returns destinations for scanning row selected by taskColumns into t.
+func taskFields(t *Task) []any {
+	return append([]any{&t.ID, &t.Name, &t.Description, &t.BoardID, &t.ColumnID, &t.Position,
+		&t.StartAt, &t.DueAt, &t.CreatedAt, &t.UpdatedAt, &t.LastModifiedBy, &t.Priority, &t.Estimate,
+		&t.DeletedAt, &t.ArchivedAt, &t.Progress.Done, &t.Progress.Total},
+		smallPersonFields((*SmallPerson)(&t.Author))...)
+}
+
+// visibleTasksSQL - condition selecting tasks, which aren't in trash themselves or together with their board.
+// Getters of tasks scoped to board or column check only task itself, board is checked by caller.
+const visibleTasksSQL = ("task.deleted_at IS NULL AND " +
+	"EXISTS (SELECT 1 FROM board WHERE board.board_id = task.board_id AND board.deleted_at IS NULL)")
+
+// unarchivedTasksSQL - condition selecting tasks, which aren't archived themselves or together with their board.
+const unarchivedTasksSQL = ("task.archived_at IS NULL AND " +
+	"NOT EXISTS (SELECT 1 FROM board WHERE board.board_id = task.board_id AND board.archived_at IS NOT NULL)")
+
+// columnEndSQL - position after the last active task in column of updated task, positionGap is passed as $2.
+const columnEndSQL = ("(SELECT COALESCE(MAX(column_task.task_position), 0) + $2 FROM task AS column_task " +
+	"WHERE column_task.column_id = task.column_id " +
+	"AND column_task.deleted_at IS NULL AND column_task.archived_at IS NULL)")
+
+// taskSelectSQL - beginning of query for selecting tasks with their authors, see TaskModel.getMany.
+const taskSelectSQL = "SELECT " + taskColumns + " FROM task JOIN person ON person.person_id = task.author_id "
+
+// subtaskColumns - columns of subtask table, scanned by subtaskFields.
+const subtaskColumns = ("subtask.subtask_id, subtask.subtask_name, subtask.parent_task_id, " +
+	"subtask.created_at, subtask.updated_at, subtask.is_done, subtask.subtask_position, subtask.assignee_id")
+
+// subtaskFields - returns destinations for scanning row selected by subtaskColumns into s.
+func subtaskFields(s *Subtask) []any {
+	return []any{&s.ID, &s.Name, &s.ParentTaskID, &s.CreatedAt, &s.UpdatedAt, &s.IsDone, &s.Position, &s.AssigneeID}
+}
+
 // TaskModel - struct that implements TaskManager interface for interacting with task table in db.
 type TaskModel struct {
 	DB DBConn
 }
 
 // Create - Creates new row in table 'task' with values from `t` fields,
+// task is placed at the end of column `t.ColumnID`, or at the end of the first
+// board column if `t.ColumnID` is 0, task without priority gets PriorityNone.
+// Person set by WithActor in ctx is saved as last modifier of task.
 // Returning created Task.
 //
 // Don't use directly, to create new task use BoardModel.AddTaskToBoard.
 func (tm TaskModel) Create(ctx context.Context, t Task) (Task, error) {
-	sql := ("WITH inserted_task AS (" +
+	// locking board row serializes positions changes on the board, see TaskModel.Move
+	lockColumnSQL := ("SELECT column_id FROM \"column\" " +
+		"JOIN board ON board.board_id = \"column\".board_id " +
+		"WHERE board.board_id = $1 AND ($2 = 0 OR column_id = $2) " +
+		"ORDER BY column_position LIMIT 1 FOR NO KEY UPDATE OF board;")
+
+	insertTaskSQL := ("WITH inserted_task AS (" +
 		"INSERT INTO task " +
-		"(task_name, task_description, board_id, author_id) " +
-		"VALUES ($1, $2, $3, $4) RETURNING *) " +
-		"SELECT inserted_task.*, username, first_name, last_name, email " +
-		"FROM inserted_task JOIN person ON person_id = author_id;")
+		"(task_name, task_description, board_id, author_id, column_id, task_position, start_at, due_at, " +
+		"last_modified_by, task_priority, estimate) " +
+		"SELECT $1::VARCHAR, $2::VARCHAR, $3::INTEGER, $4::INTEGER, $5::INTEGER, " +
+		"COALESCE(MAX(task_position), 0) + $6, $7::TIMESTAMPTZ, $8::TIMESTAMPTZ, $9::INTEGER, " +
+		"COALESCE(NULLIF($10::VARCHAR, ''), 'none'), $11::DOUBLE PRECISION " +
+		"FROM task WHERE column_id = $5 " +
+		"RETURNING task_id, task_name, task_description, board_id, author_id, column_id, task_position, " +
+		"start_at, due_at, created_at, updated_at, last_modified_by, task_priority, estimate, deleted_at, " +
+		"archived_at) " +
+		"SELECT " + taskColumns + " " +
+		"FROM inserted_task AS task JOIN person ON person.person_id = task.author_id;")
 
 	var createdTask Task
-	err := tm.DB.QueryRow(ctx, sql,
-		t.Name,
-		t.Description,
-		t.BoardID,
-		t.Author.ID,
-	).Scan(
-		&createdTask.ID,
-		&createdTask.Name,
-		&createdTask.Description,
-		&createdTask.BoardID,
-		&createdTask.Author.ID,
-		&createdTask.Author.Username,
-		&createdTask.Author.FirstName,
-		&createdTask.Author.LastName,
-		&createdTask.Author.Email,
-	)
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		var columnID uint32
+		if err := tx.QueryRow(ctx, lockColumnSQL, t.BoardID, t.ColumnID).Scan(&columnID); err != nil {
+			return err
+		}
+
+		var err error
+		createdTask, err = queryRow(ctx, tx, taskFields, insertTaskSQL,
+			t.Name,
+			t.Description,
+			t.BoardID,
+			t.Author.ID,
+			columnID,
+			positionGap,
+			t.StartAt,
+			t.DueAt,
+			actorID(ctx),
+			t.Priority,
+			t.Estimate,
+		)
+		return err
+	})
 
 	if err != nil {
-		return Task{}, fmt.Errorf("CreateTask -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.Create() -> %w", dbError(err))
 	}
 
 	return createdTask, nil
 }
 
-// DeleteByID - deletes row from table 'task'.
+// DeleteByID - moves task to trash, task in trash is hidden from getters with all its relations
+// and can be brought back by Restore, until it is purged by PurgeDeleted.
 func (tm TaskModel) DeleteByID(ctx context.Context, taskID uint32) error {
-	sql := "DELETE FROM task WHERE task_id = $1;"
-	_, err := tm.DB.Exec(ctx, sql, taskID)
+	sql := "UPDATE task SET deleted_at = now() WHERE task_id = $1 AND deleted_at IS NULL;"
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, sql, taskID)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("TaskModel.DeleteByID() -> %w", err)
+		return fmt.Errorf("TaskModel.DeleteByID() -> %w", dbError(err))
 	}
 	return nil
 }
 
-// GetByID - searching for task with task_id=taskID, returning Task.
-func (tm TaskModel) GetByID(ctx context.Context, taskID uint32) (Task, error) {
-	sql := ("SELECT task.*, " +
-		"person.username, person.first_name, person.last_name, person.email " +
-		"FROM task " +
-		"JOIN person ON person_id = author_id " +
-		"WHERE task_id = $1")
-
-	var obtainedTask Task
-	err := tm.DB.QueryRow(ctx, sql, taskID).Scan(
-		&obtainedTask.ID,
-		&obtainedTask.Name,
-		&obtainedTask.Description,
-		&obtainedTask.BoardID,
-		&obtainedTask.Author.ID,
-		&obtainedTask.Author.Username,
-		&obtainedTask.Author.FirstName,
-		&obtainedTask.Author.LastName,
-		&obtainedTask.Author.Email,
+// Restore - brings task back from trash with all its relations, task is placed at the end of its column,
+// as its position may be taken by other task. Returns ErrNotFound if task isn't in trash of board with boardID,
+// or board itself is in trash. Returning restored Task.
+func (tm TaskModel) Restore(ctx context.Context, boardID, taskID uint32) (Task, error) {
+	const (
+		// locking board row serializes positions changes on the board, see TaskModel.Move
+		lockBoardSQL = ("SELECT board.board_id FROM task JOIN board ON board.board_id = task.board_id " +
+			"WHERE task.task_id = $1 AND task.board_id = $2 AND task.deleted_at IS NOT NULL " +
+			"AND board.deleted_at IS NULL FOR NO KEY UPDATE OF board;")
+
+		restoreSQL = ("UPDATE task SET deleted_at = NULL, task_position = " + columnEndSQL + ", " +
+			"last_modified_by = COALESCE($3, last_modified_by) " +
+			"WHERE task_id = $1;")
 	)
 
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, lockBoardSQL, taskID, boardID).Scan(&boardID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, restoreSQL, taskID, positionGap, actorID(ctx))
+		return err
+	})
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Restore() -> %w", dbError(err))
+	}
+
+	restoredTask, err := tm.GetByID(ctx, taskID)
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Restore() -> %w", dbError(err))
+	}
+	return restoredTask, nil
+}
+
+// GetDeleted - returns tasks of board, that are in trash, recently deleted first.
+func (tm TaskModel) GetDeleted(ctx context.Context, boardID uint32) ([]Task, error) {
+	clauses := ("WHERE task.board_id = $1 AND task.deleted_at IS NOT NULL " +
+		"ORDER BY task.deleted_at DESC, task.task_id")
+
+	tasks, err := tm.getMany(ctx, newLoadOptions(nil), clauses, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.GetDeleted() -> %w", dbError(err))
+	}
+	return tasks, nil
+}
+
+// PurgeDeleted - permanently deletes tasks moved to trash before deletedBefore,
+// returns count of deleted tasks.
+func (tm TaskModel) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	sql := "DELETE FROM task WHERE deleted_at < $1;"
+
+	var purged int64
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, sql, deletedBefore)
+		purged = cmdTag.RowsAffected()
+		return err
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.GetByID() -> %w", err)
+		return 0, fmt.Errorf("TaskModel.PurgeDeleted() -> %w", dbError(err))
 	}
+	return purged, nil
+}
 
-	obtainedTask, err = tm.loadEverything(ctx, obtainedTask)
+// Archive - archives task, archived task keeps all its data, but is hidden from board, column and
+// person views until it is brought back by Unarchive, archiving archived task does nothing.
+// Person set by WithActor in ctx is saved as last modifier of task.
+// Returning archived Task.
+func (tm TaskModel) Archive(ctx context.Context, taskID uint32) (Task, error) {
+	sql := ("UPDATE task SET archived_at = COALESCE(archived_at, now()), " +
+		"last_modified_by = COALESCE($2, last_modified_by) " +
+		"WHERE task_id = $1 AND deleted_at IS NULL RETURNING task_id;")
+
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, taskID, actorID(ctx)).Scan(&taskID)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.GetByID() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.Archive() -> %w", dbError(err))
 	}
 
-	return obtainedTask, nil
+	archivedTask, err := tm.GetByID(ctx, taskID)
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Archive() -> %w", dbError(err))
+	}
+	return archivedTask, nil
+}
+
+// Unarchive - brings archived task back to board, task is placed at the end of its column like by Restore,
+// unarchiving active task does nothing. Person set by WithActor in ctx is saved as last modifier of task.
+// Returning unarchived Task.
+func (tm TaskModel) Unarchive(ctx context.Context, taskID uint32) (Task, error) {
+	const (
+		// locking board row serializes positions changes on the board, see TaskModel.Move
+		lockBoardSQL = ("SELECT board.board_id FROM task JOIN board ON board.board_id = task.board_id " +
+			"WHERE task.task_id = $1 AND task.deleted_at IS NULL AND board.deleted_at IS NULL " +
+			"FOR NO KEY UPDATE OF board;")
+
+		unarchiveSQL = ("UPDATE task SET archived_at = NULL, task_position = " + columnEndSQL + ", " +
+			"last_modified_by = COALESCE($3, last_modified_by) " +
+			"WHERE task_id = $1 AND archived_at IS NOT NULL;")
+	)
+
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		var boardID uint32
+		if err := tx.QueryRow(ctx, lockBoardSQL, taskID).Scan(&boardID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, unarchiveSQL, taskID, positionGap, actorID(ctx))
+		return err
+	})
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Unarchive() -> %w", dbError(err))
+	}
+
+	unarchivedTask, err := tm.GetByID(ctx, taskID)
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Unarchive() -> %w", dbError(err))
+	}
+	return unarchivedTask, nil
+}
+
+// Update - updates row in table 'task' with non-nil fields of `update`,
+// person set by WithActor in ctx is saved as last modifier of task.
+// Returning updated Task.
+func (tm TaskModel) Update(ctx context.Context, taskID uint32, update TaskUpdate) (Task, error) {
+	sql := ("UPDATE task SET " +
+		"task_name = COALESCE($2, task_name), " +
+		"task_description = COALESCE($3, task_description), " +
+		"start_at = CASE WHEN $6 THEN NULL ELSE COALESCE($4, start_at) END, " +
+		"due_at = CASE WHEN $7 THEN NULL ELSE COALESCE($5, due_at) END, " +
+		"last_modified_by = COALESCE($8, last_modified_by), " +
+		"task_priority = COALESCE($9, task_priority), " +
+		"estimate = CASE WHEN $11 THEN NULL ELSE COALESCE($10, estimate) END " +
+		"WHERE task_id = $1 AND deleted_at IS NULL RETURNING task_id;")
+
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, taskID, update.Name, update.Description,
+			update.StartAt, update.DueAt, update.ClearStartAt, update.ClearDueAt, actorID(ctx),
+			update.Priority, update.Estimate, update.ClearEstimate).Scan(&taskID)
+	})
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Update() -> %w", dbError(err))
+	}
+
+	updatedTask, err := tm.GetByID(ctx, taskID)
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Update() -> %w", dbError(err))
+	}
+	return updatedTask, nil
+}
+
+// GetByID - searching for task with task_id=taskID, returning Task. Archived task is returned too.
+func (tm TaskModel) GetByID(ctx context.Context, taskID uint32, opts ...LoadOption) (Task, error) {
+	tasks, err := tm.getMany(ctx, newLoadOptions(opts), "WHERE task.task_id = $1 AND "+visibleTasksSQL, taskID)
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.GetByID() -> %w", dbError(err))
+	}
+
+	if len(tasks) == 0 {
+		return Task{}, fmt.Errorf("TaskModel.GetByID() -> %w", dbError(pgx.ErrNoRows))
+	}
+	return tasks[0], nil
+}
+
+// GetDueBetween - returns active tasks of board with due date in [from, to), ordered by due date.
+// Tasks of archived board or board in trash aren't returned.
+func (tm TaskModel) GetDueBetween(ctx context.Context, boardID uint32, from, to time.Time) ([]Task, error) {
+	clauses := ("WHERE task.board_id = $1 AND task.due_at >= $2 AND task.due_at < $3 " +
+		"AND " + visibleTasksSQL + " AND " + unarchivedTasksSQL + " " +
+		"ORDER BY task.due_at, task.task_id")
+
+	tasks, err := tm.getMany(ctx, newLoadOptions(nil), clauses, boardID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.GetDueBetween() -> %w", dbError(err))
+	}
+	return tasks, nil
+}
+
+// GetOverdueByAssignee - returns active tasks assigned to person with due date before at, ordered by due date.
+func (tm TaskModel) GetOverdueByAssignee(ctx context.Context, personID uint32, at time.Time) ([]Task, error) {
+	clauses := ("JOIN assignee ON assignee.ref_task_id = task.task_id " +
+		"WHERE assignee.assignee_id = $1 AND task.due_at < $2 " +
+		"AND " + visibleTasksSQL + " AND " + unarchivedTasksSQL + " " +
+		"ORDER BY task.due_at, task.task_id")
+
+	tasks, err := tm.getMany(ctx, newLoadOptions(nil), clauses, personID, at)
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.GetOverdueByAssignee() -> %w", dbError(err))
+	}
+	return tasks, nil
+}
+
+// GetByFilter - returns tasks of board matching filter, ordered by filter.Sort.
+func (tm TaskModel) GetByFilter(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	clauses, args, err := filter.clauses()
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.GetByFilter() -> %w", err)
+	}
+
+	tasks, err := tm.getMany(ctx, newLoadOptions(nil), clauses, args...)
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.GetByFilter() -> %w", dbError(err))
+	}
+	return tasks, nil
+}
+
+// clauses - returns clauses for TaskModel.getMany selecting tasks matching f, and their args.
+func (f TaskFilter) clauses() (string, []any, error) {
+	args := []any{f.BoardID}
+	clauses := ("JOIN \"column\" ON \"column\".column_id = task.column_id " +
+		"WHERE task.board_id = $1 AND task.deleted_at IS NULL")
+
+	if !f.IncludeArchived {
+		clauses += " AND task.archived_at IS NULL"
+	}
+
+	if f.ColumnID != 0 {
+		args = append(args, f.ColumnID)
+		clauses += fmt.Sprintf(" AND task.column_id = $%d", len(args))
+	}
+
+	if len(f.Priorities) != 0 {
+		args = append(args, priorityStrings(f.Priorities))
+		clauses += fmt.Sprintf(" AND task.task_priority = ANY($%d)", len(args))
+	}
+
+	if f.MinEstimate != nil {
+		args = append(args, *f.MinEstimate)
+		clauses += fmt.Sprintf(" AND task.estimate >= $%d", len(args))
+	}
+
+	if f.MaxEstimate != nil {
+		args = append(args, *f.MaxEstimate)
+		clauses += fmt.Sprintf(" AND task.estimate <= $%d", len(args))
+	}
+
+	const byPosition = "\"column\".column_position, task.task_position"
+	switch f.Sort {
+	case "", SortByPosition:
+		clauses += " ORDER BY " + byPosition
+	case SortByPriority:
+		args = append(args, priorityStrings(TaskPriorities))
+		clauses += fmt.Sprintf(" ORDER BY array_position($%d, task.task_priority) DESC, %s", len(args), byPosition)
+	case SortByEstimate:
+		clauses += " ORDER BY task.estimate NULLS LAST, " + byPosition
+	default:
+		return "", nil, newError(ErrInvalidInput, "unknown sort of tasks %q", f.Sort)
+	}
+	return clauses, args, nil
+}
+
+// priorityStrings - converts priorities to strings for passing them to query as VARCHAR[].
+func priorityStrings(priorities []TaskPriority) []string {
+	strs := make([]string, len(priorities))
+	for i, priority := range priorities {
+		strs[i] = string(priority)
+	}
+	return strs
+}
+
+// getMany - returns tasks selected by taskSelectSQL followed by clauses with args, tags, subtasks and
+// assignees of all tasks are loaded by one query each, regardless of tasks count.
+func (tm TaskModel) getMany(ctx context.Context, o loadOptions, clauses string, args ...any) ([]Task, error) {
+	tasks, err := queryRows(ctx, tm.DB, taskFields, taskSelectSQL+clauses, args...)
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.getMany() -> %w", dbError(err))
+	}
+
+	tasks, err = tm.loadEverything(ctx, tasks, o)
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.getMany() -> %w", dbError(err))
+	}
+	return tasks, nil
 }
 
 // AddAssigneeToTask - assigning task to person in assignee table.
 func (tm TaskModel) AddAssigneeToTask(ctx context.Context, assignee TaskAssignee, task Task) (Task, error) {
 	sql := "INSERT INTO assignee (ref_task_id, assignee_id) VALUES ($1, $2);"
-	_, err := tm.DB.Exec(ctx, sql, task.ID, assignee.ID)
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql, task.ID, assignee.ID); err != nil {
+			return err
+		}
+		return touchTask(ctx, tx, &task)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.AssignTaskToPerson() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.AssignTaskToPerson() -> %w", dbError(err))
 	}
 
-	task, err = tm.loadAssignees(ctx, task)
+	task, err = loadOne(ctx, task, tm.loadAssignees)
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.AssignTaskToPerson() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.AssignTaskToPerson() -> %w", dbError(err))
 	}
 	return task, nil
 }
@@ -133,14 +549,19 @@ func (tm TaskModel) AddAssigneeToTask(ctx context.Context, assignee TaskAssignee
 // RemoveAssignFromTask - removes row from assignee table;.
 func (tm TaskModel) RemoveAssignFromTask(ctx context.Context, assignee TaskAssignee, task Task) (Task, error) {
 	sql := "DELETE FROM assignee WHERE ref_task_id = $1 AND assignee_id = $2"
-	_, err := tm.DB.Exec(ctx, sql, task.ID, assignee.ID)
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql, task.ID, assignee.ID); err != nil {
+			return err
+		}
+		return touchTask(ctx, tx, &task)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.RemoveAssignFromTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.RemoveAssignFromTask() -> %w", dbError(err))
 	}
 
 	updateTask, err := tm.GetByID(ctx, task.ID)
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.RemoveAssignFromTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.RemoveAssignFromTask() -> %w", dbError(err))
 	}
 	return updateTask, nil
 }
@@ -148,14 +569,19 @@ func (tm TaskModel) RemoveAssignFromTask(ctx context.Context, assignee TaskAssig
 // AddTagToTask - add tag to task in task_tag table.
 func (tm TaskModel) AddTagToTask(ctx context.Context, tag Tag, task Task) (Task, error) {
 	sql := "INSERT INTO task_tag (ref_tag_id, ref_task_id) VALUES ($1, $2);"
-	_, err := tm.DB.Exec(ctx, sql, tag.ID, task.ID)
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql, tag.ID, task.ID); err != nil {
+			return err
+		}
+		return touchTask(ctx, tx, &task)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.AddTagToTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.AddTagToTask() -> %w", dbError(err))
 	}
 
-	task, err = tm.loadTags(ctx, task)
+	task, err = loadOne(ctx, task, tm.loadTags)
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.AddTagToTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.AddTagToTask() -> %w", dbError(err))
 	}
 
 	return task, nil
@@ -164,157 +590,452 @@ func (tm TaskModel) AddTagToTask(ctx context.Context, tag Tag, task Task) (Task,
 // RemoveTagFromTask - removes row from task_tag table;.
 func (tm TaskModel) RemoveTagFromTask(ctx context.Context, tag Tag, task Task) (Task, error) {
 	sql := "DELETE FROM task_tag WHERE ref_tag_id = $1 AND ref_task_id = $2"
-	_, err := tm.DB.Exec(ctx, sql, tag.ID, task.ID)
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql, tag.ID, task.ID); err != nil {
+			return err
+		}
+		return touchTask(ctx, tx, &task)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.RemoveTagFromTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.RemoveTagFromTask() -> %w", dbError(err))
 	}
 
 	updatedTask, err := tm.GetByID(ctx, task.ID)
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.RemoveTagFromTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.RemoveTagFromTask() -> %w", dbError(err))
 	}
 	return updatedTask, nil
 }
 
-// AddSubtaskToTask - add subtask to task in subtask table.
+// AddSubtaskToTask - add subtask to task in subtask table, subtask is placed at the end of task checklist.
+// subtask.ParentTaskID is ignored.
 func (tm TaskModel) AddSubtaskToTask(ctx context.Context, subtask Subtask, task Task) (Task, error) {
-	sql := ("INSERT INTO subtask (subtask_name, parent_task_id) " +
-		"VALUES ($1, $2);")
-	_, err := tm.DB.Exec(ctx, sql, subtask.Name, subtask.ParentTaskID)
+	sql := ("INSERT INTO subtask (subtask_name, parent_task_id, is_done, assignee_id, subtask_position) " +
+		"SELECT $1::VARCHAR, $2::INTEGER, $3::BOOLEAN, $4::INTEGER, COALESCE(MAX(subtask_position), 0) + 1 " +
+		"FROM subtask WHERE parent_task_id = $2;")
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, sql, subtask.Name, task.ID, subtask.IsDone, subtask.AssigneeID)
+		if err != nil {
+			return err
+		}
+		return touchTask(ctx, tx, &task)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.AddSubtaskToTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.AddSubtaskToTask() -> %w", dbError(err))
 	}
 
-	task, err = tm.loadSubtasks(ctx, task)
+	task, err = loadOne(ctx, task, tm.loadSubtasks)
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.AddSubtaskToTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.AddSubtaskToTask() -> %w", dbError(err))
 	}
 
 	return task, nil
 }
 
-// RemoveSubtaskFromTask - removes row from subtask table;.
+// RemoveSubtaskFromTask - removes row from subtask table, returns ErrNotFound if subtask doesn't belong to task.
 func (tm TaskModel) RemoveSubtaskFromTask(ctx context.Context, subtask Subtask, task Task) (Task, error) {
-	sql := "DELETE FROM subtask WHERE subtask_id = $1"
-	_, err := tm.DB.Exec(ctx, sql, subtask.ID)
+	sql := "DELETE FROM subtask WHERE subtask_id = $1 AND parent_task_id = $2"
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, sql, subtask.ID, task.ID)
+		if err != nil {
+			return err
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return newError(ErrNotFound, "subtask %d of task %d doesn't exist", subtask.ID, task.ID)
+		}
+		return touchTask(ctx, tx, &task)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.RemoveSubtaskFromTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.RemoveSubtaskFromTask() -> %w", dbError(err))
 	}
 
 	updatedTask, err := tm.GetByID(ctx, task.ID)
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.RemoveSubtaskFromTask() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.RemoveSubtaskFromTask() -> %w", dbError(err))
 	}
 	return updatedTask, nil
 }
 
-// loadEverything - combines loadTags, loadSubtasks, loadAssignees in one method.
-func (tm TaskModel) loadEverything(ctx context.Context, task Task) (Task, error) {
-	task, err := tm.loadTags(ctx, task)
+// UpdateSubtask - updates subtask of task with non-nil fields of `update`,
+// returns ErrNotFound if subtask doesn't belong to task.
+// Returning Task with updated subtask.
+func (tm TaskModel) UpdateSubtask(ctx context.Context, subtask Subtask, task Task, update SubtaskUpdate) (Task, error) {
+	sql := ("UPDATE subtask SET " +
+		"subtask_name = COALESCE($3, subtask_name), " +
+		"is_done = COALESCE($4, is_done), " +
+		"assignee_id = CASE WHEN $6 THEN NULL ELSE COALESCE($5, assignee_id) END " +
+		"WHERE subtask_id = $1 AND parent_task_id = $2;")
+
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, sql, subtask.ID, task.ID,
+			update.Name, update.IsDone, update.AssigneeID, update.ClearAssignee)
+		if err != nil {
+			return err
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return newError(ErrNotFound, "subtask %d of task %d doesn't exist", subtask.ID, task.ID)
+		}
+		return touchTask(ctx, tx, &task)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.loadEverything() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.UpdateSubtask() -> %w", dbError(err))
 	}
 
-	task, err = tm.loadSubtasks(ctx, task)
+	task, err = loadOne(ctx, task, tm.loadSubtasks)
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.loadEverything() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.UpdateSubtask() -> %w", dbError(err))
 	}
+	return task, nil
+}
+
+// MoveSubtask - moves subtask to position (zero-based index) in checklist of task, position is clamped
+// to bounds of checklist, returns ErrNotFound if subtask doesn't belong to task.
+// Returning Task with reordered subtasks.
+func (tm TaskModel) MoveSubtask(ctx context.Context, subtask Subtask, task Task, position int) (Task, error) {
+	const (
+		lockTaskSQL = "SELECT task_id FROM task WHERE task_id = $1 FOR NO KEY UPDATE;"
+
+		subtasksSQL = ("SELECT subtask_id FROM subtask WHERE parent_task_id = $1 " +
+			"ORDER BY subtask_position, subtask_id;")
+
+		reorderSQL = ("UPDATE subtask SET subtask_position = ordered.position " +
+			"FROM unnest($1::INTEGER[]) WITH ORDINALITY AS ordered(subtask_id, position) " +
+			"WHERE subtask.subtask_id = ordered.subtask_id;")
+	)
+
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		var taskID uint32
+		if err := tx.QueryRow(ctx, lockTaskSQL, task.ID).Scan(&taskID); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, subtasksSQL, task.ID)
+		if err != nil {
+			return err
+		}
+		subtaskIDs, err := pgx.CollectRows(rows, pgx.RowTo[uint32])
+		if err != nil {
+			return err
+		}
+
+		index := -1
+		for i, subtaskID := range subtaskIDs {
+			if subtaskID == subtask.ID {
+				index = i
+			}
+		}
+		if index == -1 {
+			return newError(ErrNotFound, "subtask %d of task %d doesn't exist", subtask.ID, task.ID)
+		}
+		subtaskIDs = append(subtaskIDs[:index], subtaskIDs[index+1:]...)
 
-	task, err = tm.loadAssignees(ctx, task)
+		position = clampPosition(position, len(subtaskIDs))
+		subtaskIDs = append(subtaskIDs[:position], append([]uint32{subtask.ID}, subtaskIDs[position:]...)...)
+
+		if _, err := tx.Exec(ctx, reorderSQL, subtaskIDs); err != nil {
+			return err
+		}
+
+		// checklist is renumbered by move, so index of subtask is logged instead of its position
+		err = logMove(ctx, tx, "subtask", subtask.ID, task.ID,
+			map[string]any{"index": index}, map[string]any{"index": position})
+		if err != nil {
+			return err
+		}
+		return touchTask(ctx, tx, &task)
+	})
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.loadEverything() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.MoveSubtask() -> %w", dbError(err))
 	}
 
-	task, err = tm.loadTags(ctx, task)
+	task, err = loadOne(ctx, task, tm.loadSubtasks)
 	if err != nil {
-		return Task{}, fmt.Errorf("TaskModel.loadEverything() -> %w", err)
+		return Task{}, fmt.Errorf("TaskModel.MoveSubtask() -> %w", dbError(err))
 	}
-
 	return task, nil
 }
 
-// loadSubtasks - loading subtasks to Task.Subtasks list.
-func (tm TaskModel) loadSubtasks(ctx context.Context, task Task) (Task, error) {
-	sql := ("SELECT subtask_id, subtask_name, parent_task_id " +
-		"FROM task JOIN subtask ON parent_task_id = task_id " +
-		"WHERE task_id = $1")
+// touchTask - marks task as changed now by person set by WithActor in ctx, used when related data
+// of task is changed, because triggers update updated_at only when task row itself is changed.
+// Progress of task is refreshed too, as it may be changed by subtasks.
+func touchTask(ctx context.Context, dbConn DBConn, task *Task) error {
+	sql := ("UPDATE task SET updated_at = now(), last_modified_by = COALESCE($2, last_modified_by) " +
+		"WHERE task_id = $1 RETURNING updated_at, last_modified_by, " + taskProgressColumns + ";")
+	return dbConn.QueryRow(ctx, sql, task.ID, actorID(ctx)).Scan(&task.UpdatedAt, &task.LastModifiedBy,
+		&task.Progress.Done, &task.Progress.Total)
+}
 
-	rows, _ := tm.DB.Query(ctx, sql, task.ID)
-	defer rows.Close()
+// Move - moves task to column on position (zero-based index among column tasks),
+// task can be moved within it's column or to other column of the same board.
+// Position is clamped to bounds of the column, archived tasks can't be moved and aren't counted.
+// Returning moved Task.
+//
+// Task gets rank in the middle of the gap between new neighbours, column is renumbered
+// only when there is no free rank between them. Moves and creations of tasks on the same
+// board are serialized by locking the board row, so concurrent moves never produce
+// duplicate positions.
+func (tm TaskModel) Move(ctx context.Context, task Task, column Column, position int) (Task, error) {
+	if task.BoardID != column.BoardID {
+		return Task{}, fmt.Errorf("TaskModel.Move() -> %w",
+			newError(ErrInvalidInput, "task.BoardID(%d) != column.BoardID(%d)", task.BoardID, column.BoardID))
+	}
+
+	const (
+		lockBoardSQL = "SELECT board_id FROM board WHERE board_id = $1 FOR NO KEY UPDATE;"
+
+		currentSQL = ("SELECT column_id, task_position FROM task " +
+			"WHERE task_id = $1 AND board_id = $2 AND deleted_at IS NULL AND archived_at IS NULL;")
+
+		positionsSQL = ("SELECT task_position FROM task " +
+			"WHERE column_id = $1 AND task_id <> $2 AND deleted_at IS NULL AND archived_at IS NULL " +
+			"ORDER BY task_position;")
 
-	var subtasks []Subtask
-	for rows.Next() {
-		var subtask Subtask
-		err := rows.Scan(&subtask.ID, &subtask.Name, &subtask.ParentTaskID)
+		moveSQL = ("UPDATE task SET column_id = $2, task_position = $3, " +
+			"last_modified_by = COALESCE($5, last_modified_by) " +
+			"WHERE task_id = $1 AND board_id = $4;")
+	)
+
+	err := inTx(ctx, tm.DB, func(tx pgx.Tx) error {
+		var boardID uint32
+		if err := tx.QueryRow(ctx, lockBoardSQL, column.BoardID).Scan(&boardID); err != nil {
+			return err
+		}
+
+		var oldColumnID uint32
+		var oldPosition int64
+		if err := tx.QueryRow(ctx, currentSQL, task.ID, column.BoardID).Scan(&oldColumnID, &oldPosition); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, positionsSQL, column.ID, task.ID)
+		if err != nil {
+			return err
+		}
+		positions, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+		if err != nil {
+			return err
+		}
+
+		position = clampPosition(position, len(positions))
+		newPosition, ok := rankBetween(positions, position)
+		if !ok {
+			newPosition, err = tm.rebalanceColumn(ctx, tx, column.ID, task.ID, position)
+			if err != nil {
+				return err
+			}
+		}
+
+		cmdTag, err := tx.Exec(ctx, moveSQL, task.ID, column.ID, newPosition, column.BoardID, actorID(ctx))
 		if err != nil {
-			return Task{}, fmt.Errorf("TaskModel.loadSubtasks() -> %w", err)
+			return err
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
 		}
 
-		subtasks = append(subtasks, subtask)
+		return logMove(ctx, tx, "task", task.ID, task.ID,
+			map[string]any{"column_id": oldColumnID, "task_position": oldPosition},
+			map[string]any{"column_id": column.ID, "task_position": newPosition})
+	})
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Move() -> %w", dbError(err))
+	}
+
+	movedTask, err := tm.GetByID(ctx, task.ID)
+	if err != nil {
+		return Task{}, fmt.Errorf("TaskModel.Move() -> %w", dbError(err))
+	}
+	return movedTask, nil
+}
+
+// clampPosition - returns position clamped to bounds [0, length].
+func clampPosition(position, length int) int {
+	if position < 0 {
+		return 0
 	}
+	if position > length {
+		return length
+	}
+	return position
+}
 
-	if err := rows.Err(); err != nil {
-		return Task{}, fmt.Errorf("TaskModel.loadSubtasks() -> %w", err)
+// rankBetween - returns rank for inserting on index `position` of ordered `positions`,
+// returns false if there is no free rank between neighbours.
+func rankBetween(positions []int64, position int) (int64, bool) {
+	var prev int64
+	if position > 0 {
+		prev = positions[position-1]
 	}
 
-	task.Subtasks = subtasks
-	return task, nil
+	if position == len(positions) {
+		return prev + positionGap, true
+	}
+
+	next := positions[position]
+	if next-prev < 2 {
+		return 0, false
+	}
+	return prev + (next-prev)/2, true
 }
 
-// loadAssignees - loading assigness to Task.Assigness list.
-func (tm TaskModel) loadAssignees(ctx context.Context, task Task) (Task, error) {
-	sql := ("SELECT assignee_id, " +
-		"person.username, person.first_name, person.last_name, person.email " +
-		"FROM assignee JOIN person ON person_id = assignee_id " +
-		"WHERE ref_task_id = $1")
+// rebalanceColumn - renumbers tasks of column with positionGap between them, leaving free
+// rank on index `position` for moving task, returns that rank.
+func (tm TaskModel) rebalanceColumn(ctx context.Context, tx pgx.Tx,
+	columnID, movingTaskID uint32, position int) (int64, error) {
+	// Moving positions to negative values first, because unique (column_id, task_position)
+	// is checked after every updated row.
+	const (
+		negateSQL = ("UPDATE task SET task_position = -task_position " +
+			"WHERE (column_id = $1 OR task_id = $2) AND deleted_at IS NULL AND archived_at IS NULL;")
 
-	rows, _ := tm.DB.Query(ctx, sql, task.ID)
-	defer rows.Close()
+		renumberSQL = ("UPDATE task SET task_position = ranked.rank * $3 " +
+			"FROM (SELECT task_id, " +
+			"CASE WHEN row_number() OVER w > $4 THEN row_number() OVER w + 1 " +
+			"ELSE row_number() OVER w END AS rank " +
+			"FROM task WHERE column_id = $1 AND task_id <> $2 AND deleted_at IS NULL AND archived_at IS NULL " +
+			"WINDOW w AS (ORDER BY task_position DESC)) AS ranked " +
+			"WHERE task.task_id = ranked.task_id;")
+	)
 
-	var assignees []TaskAssignee
-	for rows.Next() {
-		var assignee TaskAssignee
-		err := rows.Scan(&assignee.ID, &assignee.Username, &assignee.FirstName,
-			&assignee.LastName, &assignee.Email)
-		if err != nil {
-			return Task{}, fmt.Errorf("TaskModel.loadAssigness() -> %w", err)
+	if _, err := tx.Exec(ctx, negateSQL, columnID, movingTaskID); err != nil {
+		return 0, fmt.Errorf("TaskModel.rebalanceColumn() -> %w", dbError(err))
+	}
+
+	if _, err := tx.Exec(ctx, renumberSQL, columnID, movingTaskID, positionGap, position); err != nil {
+		return 0, fmt.Errorf("TaskModel.rebalanceColumn() -> %w", dbError(err))
+	}
+
+	return int64(position+1) * positionGap, nil
+}
+
+// loadEverything - combines loadTags, loadSubtasks, loadAssignees in one method,
+// loading only data selected by o.
+func (tm TaskModel) loadEverything(ctx context.Context, tasks []Task, o loadOptions) ([]Task, error) {
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	var err error
+	if o.tags {
+		if tasks, err = tm.loadTags(ctx, tasks); err != nil {
+			return nil, fmt.Errorf("TaskModel.loadEverything() -> %w", dbError(err))
 		}
+	}
 
-		assignees = append(assignees, assignee)
+	if o.subtasks {
+		if tasks, err = tm.loadSubtasks(ctx, tasks); err != nil {
+			return nil, fmt.Errorf("TaskModel.loadEverything() -> %w", dbError(err))
+		}
 	}
 
-	if err := rows.Err(); err != nil {
-		return Task{}, fmt.Errorf("TaskModel.loadAssigness() -> %w", err)
+	if o.assignees {
+		if tasks, err = tm.loadAssignees(ctx, tasks); err != nil {
+			return nil, fmt.Errorf("TaskModel.loadEverything() -> %w", dbError(err))
+		}
 	}
 
-	task.Assignees = assignees
-	return task, nil
+	return tasks, nil
 }
 
-// loadTags - loading tags in Task.Tags slice.
-func (tm TaskModel) loadTags(ctx context.Context, task Task) (Task, error) {
-	sql := ("SELECT tag.* FROM task " +
-		"JOIN task_tag ON task_id = ref_task_id " +
-		"JOIN tag ON tag_id = ref_tag_id " +
-		"WHERE task_id = $1")
+// loadSubtasks - loading subtasks to Task.Subtasks list of each task.
+func (tm TaskModel) loadSubtasks(ctx context.Context, tasks []Task) ([]Task, error) {
+	sql := ("SELECT " + subtaskColumns + " " +
+		"FROM subtask " +
+		"WHERE subtask.parent_task_id = ANY($1) " +
+		"ORDER BY subtask.subtask_position, subtask.subtask_id")
 
-	rows, _ := tm.DB.Query(ctx, sql, task.ID)
-	defer rows.Close()
+	subtasks, err := queryRows(ctx, tm.DB, subtaskFields, sql, taskIDs(tasks))
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.loadSubtasks() -> %w", dbError(err))
+	}
 
-	var tags []Tag
-	for rows.Next() {
-		var tag Tag
-		err := rows.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.BoardID)
-		if err != nil {
-			return Task{}, fmt.Errorf("TaskModel.loadTags() -> %w", err)
-		}
-		tags = append(tags, tag)
+	index := indexTasks(tasks)
+	for _, i := range index {
+		tasks[i].Subtasks = nil
 	}
 
-	if err := rows.Err(); err != nil {
-		return Task{}, fmt.Errorf("TaskModel.loadTags() -> %w", err)
+	for _, subtask := range subtasks {
+		i := index[subtask.ParentTaskID]
+		tasks[i].Subtasks = append(tasks[i].Subtasks, subtask)
 	}
 
-	task.Tags = tags
-	return task, nil
+	return tasks, nil
+}
+
+// loadAssignees - loading assigness to Task.Assigness list of each task.
+func (tm TaskModel) loadAssignees(ctx context.Context, tasks []Task) ([]Task, error) {
+	sql := ("SELECT assignee.ref_task_id, " + smallPersonColumns + " " +
+		"FROM assignee JOIN person ON person.person_id = assignee.assignee_id " +
+		"WHERE assignee.ref_task_id = ANY($1) " +
+		"ORDER BY assignee.assignee_id")
+
+	assignees, err := queryRows(ctx, tm.DB, taskRelationFields(smallPersonFields), sql, taskIDs(tasks))
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.loadAssigness() -> %w", dbError(err))
+	}
+
+	index := indexTasks(tasks)
+	for _, i := range index {
+		tasks[i].Assignees = nil
+	}
+
+	for _, assignee := range assignees {
+		i := index[assignee.taskID]
+		tasks[i].Assignees = append(tasks[i].Assignees, TaskAssignee(assignee.value))
+	}
+
+	return tasks, nil
+}
+
+// loadTags - loading tags in Task.Tags slice of each task.
+func (tm TaskModel) loadTags(ctx context.Context, tasks []Task) ([]Task, error) {
+	sql := ("SELECT task_tag.ref_task_id, " + tagColumns + " " +
+		"FROM task_tag JOIN tag ON tag.tag_id = task_tag.ref_tag_id " +
+		"WHERE task_tag.ref_task_id = ANY($1) " +
+		"ORDER BY tag.tag_id")
+
+	tags, err := queryRows(ctx, tm.DB, taskRelationFields(tagFields), sql, taskIDs(tasks))
+	if err != nil {
+		return nil, fmt.Errorf("TaskModel.loadTags() -> %w", dbError(err))
+	}
+
+	index := indexTasks(tasks)
+	for _, i := range index {
+		tasks[i].Tags = nil
+	}
+
+	for _, tag := range tags {
+		i := index[tag.taskID]
+		tasks[i].Tags = append(tasks[i].Tags, tag.value)
+	}
+
+	return tasks, nil
+}
+
+// loadOne - applies batch loader of tasks to single task.
+func loadOne(ctx context.Context, task Task,
+	load func(ctx context.Context, tasks []Task) ([]Task, error)) (Task, error) {
+	tasks, err := load(ctx, []Task{task})
+	if err != nil {
+		return Task{}, err
+	}
+	return tasks[0], nil
+}
+
+// taskIDs - returns IDs of tasks.
+func taskIDs(tasks []Task) []uint32 {
+	ids := make([]uint32, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+// indexTasks - returns map from task ID to its index in tasks.
+func indexTasks(tasks []Task) map[uint32]int {
+	index := make(map[uint32]int, len(tasks))
+	for i, task := range tasks {
+		index[task.ID] = i
+	}
+	return index
 }
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// activityPage - response with page of activity log, newest first. NextAfterID is 'after' parameter
+// for requesting next page, it's nil if there is no more activity.
+type activityPage struct {
+	NextAfterID *uint32             `json:"next_after_id"`
+	Activity    []database.Activity `json:"activity"`
+}
+
+// newActivityPage - returns activityPage with activity, which was requested with limit.
+func newActivityPage(activity []database.Activity, limit int) activityPage {
+	page := activityPage{Activity: activity}
+	if len(activity) == limit {
+		page.NextAfterID = &activity[len(activity)-1].ID
+	}
+	return page
+}
+
+// GetBoardActivityHandler - handles getting page of board activity log, newest first, page is selected
+// by 'after' (ID of the last activity of previous page) and 'limit' query parameters.
+func (h *Handlers) GetBoardActivityHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	page, err := queryPage(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	activity, err := h.DB.Activity.GetByBoard(r.Context(), boardID, page)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, newActivityPage(activity, page.Limit))
+}
+
+// GetTaskActivityHandler - handles getting page of task activity log, newest first, paginated like
+// GetBoardActivityHandler. Activity of deleted task is available too.
+func (h *Handlers) GetTaskActivityHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	page, err := queryPage(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	activity, err := h.DB.Activity.GetByTask(r.Context(), ids[0], ids[1], page)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, newActivityPage(activity, page.Limit))
+}
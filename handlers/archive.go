@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/s4lat/gokan/auth"
+)
+
+// ArchiveBoardHandler - handles archiving of board.
+func (h *Handlers) ArchiveBoardHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.EditBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.DB.Board.Archive(r.Context(), boardID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// UnarchiveBoardHandler - handles bringing archived board back to active boards.
+func (h *Handlers) UnarchiveBoardHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.EditBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.DB.Board.Unarchive(r.Context(), boardID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// ArchiveColumnTasksHandler - handles archiving of all tasks in board column, responds with emptied column.
+func (h *Handlers) ArchiveColumnTasksHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "columnID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getColumn(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.DB.Column.ArchiveTasks(r.Context(), ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	column, err := h.getColumn(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, column)
+}
+
+// ArchiveTaskHandler - handles archiving of board task.
+func (h *Handlers) ArchiveTaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getTask(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.DB.Task.Archive(r.Context(), ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// UnarchiveTaskHandler - handles bringing archived task back to board, task is placed
+// at the end of its column.
+func (h *Handlers) UnarchiveTaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getTask(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.DB.Task.Unarchive(r.Context(), ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// loginRequest - body of login request, login is username or email.
+type loginRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// RegisterHandler - handles registration of new person.
+func (h *Handlers) RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	var form auth.RegisterForm
+	if err := readJSON(w, r, &form); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	person, err := auth.Register(r.Context(), h.DB.Person, form)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, newPersonView(person))
+}
+
+// changePasswordRequest - body of password change request.
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// tokenResponse - body of response with issued API token.
+type tokenResponse struct {
+	Token   string           `json:"token"`
+	Session database.Session `json:"session"`
+}
+
+// LoginHandler - handles checking of person credentials, starts browser session on success.
+func (h *Handlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := readJSON(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	person, err := auth.Login(r.Context(), h.DB.Person, req.Login, req.Password)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	cookieValue, session, err := h.Sessions.StartCookie(r.Context(), person.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.setSessionCookie(w, cookieValue, session)
+	h.writeJSON(w, http.StatusOK, newPersonView(person))
+}
+
+// LogoutHandler - handles revocation of current session, cookie or token.
+func (h *Handlers) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := sessionFromContext(r.Context())
+	if !ok {
+		h.writeError(w, errors.New("LogoutHandler() -> no session in request context"))
+		return
+	}
+
+	if err := h.DB.Session.RevokeByID(r.Context(), session.ID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if session.Kind == database.SessionCookie {
+		h.setSessionCookie(w, "", database.Session{})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangePasswordHandler - handles changing of authenticated person password,
+// all sessions of person including current one are revoked.
+func (h *Handlers) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := sessionFromContext(r.Context())
+	if !ok {
+		h.writeError(w, errors.New("ChangePasswordHandler() -> no session in request context"))
+		return
+	}
+
+	var req changePasswordRequest
+	if err := readJSON(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	err := auth.ChangePassword(r.Context(), h.DB.Person, session.PersonID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if session.Kind == database.SessionCookie {
+		h.setSessionCookie(w, "", database.Session{})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MeHandler - handles getting of authenticated person.
+func (h *Handlers) MeHandler(w http.ResponseWriter, r *http.Request) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		h.writeError(w, errors.New("MeHandler() -> no person in request context"))
+		return
+	}
+
+	person, err := h.DB.Person.GetByID(r.Context(), person.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newPersonView(person))
+}
+
+// IssueTokenHandler - handles issuing of API token for authenticated person.
+func (h *Handlers) IssueTokenHandler(w http.ResponseWriter, r *http.Request) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		h.writeError(w, errors.New("IssueTokenHandler() -> no person in request context"))
+		return
+	}
+
+	token, session, err := h.Sessions.IssueToken(r.Context(), person.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, tokenResponse{Token: token, Session: session})
+}
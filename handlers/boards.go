@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// contributorRequest - body of request for adding contributor to board, empty role means member.
+type contributorRequest struct {
+	Role     database.ContributorRole `json:"role"`
+	PersonID uint32                   `json:"person_id"`
+}
+
+// transferOwnershipRequest - body of request for transferring board ownership to contributor.
+type transferOwnershipRequest struct {
+	PersonID uint32 `json:"person_id"`
+}
+
+// contributorRoleRequest - body of request for changing role of contributor.
+type contributorRoleRequest struct {
+	Role database.ContributorRole `json:"role"`
+}
+
+// CreateBoardHandler - handles creation of board, authenticated person becomes board owner.
+func (h *Handlers) CreateBoardHandler(w http.ResponseWriter, r *http.Request) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		h.writeError(w, auth.ErrUnauthenticated)
+		return
+	}
+
+	var board database.Board
+	if err := readJSON(w, r, &board); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	board.Owner = database.BoardOwner(person.Small())
+
+	board, err := h.DB.Board.Create(r.Context(), board)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err = h.DB.Board.GetByID(r.Context(), board.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, board)
+}
+
+// GetBoardHandler - handles getting board by ID, archived tasks are included if 'archived' query parameter is true.
+// Board tasks are filtered by 'filter' query parameter, see database.ParseTaskQuery for its syntax.
+func (h *Handlers) GetBoardHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	archived, err := queryBool(r, "archived")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	query, err := database.ParseTaskQuery(r.URL.Query().Get("filter"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	opts := []database.LoadOption{database.WithEverything(), database.WithQuery(query)}
+	if archived {
+		opts = append(opts, database.WithArchived())
+	}
+
+	board, err := h.authorizeBoard(r, boardID, auth.ReadBoard, opts...)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// UpdateBoardHandler - handles partial update of board.
+func (h *Handlers) UpdateBoardHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.EditBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var update database.BoardUpdate
+	if err := readJSON(w, r, &update); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.DB.Board.Update(r.Context(), boardID, update)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// DeleteBoardHandler - handles deletion of board.
+func (h *Handlers) DeleteBoardHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.DeleteBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := h.DB.Board.DeleteByID(r.Context(), boardID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddContributorHandler - handles adding person to board contributors.
+func (h *Handlers) AddContributorHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var req contributorRequest
+	if err := readJSON(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.authorizeBoard(r, boardID, auth.ManageContributors, database.WithEverything())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	person, err := h.DB.Person.GetSmallByID(r.Context(), req.PersonID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err = h.DB.Board.AddContributorToBoard(r.Context(), person.AsContributor(req.Role), board)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// SetContributorRoleHandler - handles changing role of board contributor.
+func (h *Handlers) SetContributorRoleHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "personID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var req contributorRoleRequest
+	if err := readJSON(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.authorizeBoard(r, ids[0], auth.ManageContributors, database.WithEverything())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err = h.DB.Board.SetContributorRole(r.Context(), database.Contributor{ID: ids[1], Role: req.Role}, board)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// TransferOwnershipHandler - handles making contributor the owner of board.
+func (h *Handlers) TransferOwnershipHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var req transferOwnershipRequest
+	if err := readJSON(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.authorizeBoard(r, boardID, auth.TransferOwnership)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if role := auth.RoleOf(board, req.PersonID); role == auth.RoleOutsider || role == auth.RoleOwner {
+		h.writeError(w, fmt.Errorf("%w: new owner must be board contributor", errBadRequest))
+		return
+	}
+
+	board, err = h.DB.Board.TransferOwnership(r.Context(), board, database.Contributor{ID: req.PersonID})
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// RemoveContributorHandler - handles removing person from board contributors,
+// contributor can remove themselves to leave the board.
+func (h *Handlers) RemoveContributorHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "personID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	permission := auth.ManageContributors
+	if person, ok := PersonFromContext(r.Context()); ok && person.ID == ids[1] {
+		permission = auth.ReadBoard
+	}
+
+	board, err := h.authorizeBoard(r, ids[0], permission, database.WithEverything())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err = h.DB.Board.RemoveContributorFromBoard(r.Context(), database.Contributor{ID: ids[1]}, board)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// CreateColumnHandler - handles creation of column in board.
+func (h *Handlers) CreateColumnHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.ManageColumns); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var column database.Column
+	if err := readJSON(w, r, &column); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	column.BoardID = boardID
+
+	column, err = h.DB.Column.Create(r.Context(), column)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, column)
+}
+
+// GetColumnHandler - handles getting board column by ID.
+func (h *Handlers) GetColumnHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "columnID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	column, err := h.getColumn(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, column)
+}
+
+// UpdateColumnHandler - handles partial update of board column.
+func (h *Handlers) UpdateColumnHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "columnID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ManageColumns); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var update database.ColumnUpdate
+	if err := readJSON(w, r, &update); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getColumn(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	column, err := h.DB.Column.Update(r.Context(), ids[1], update)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, column)
+}
+
+// DeleteColumnHandler - handles deletion of board column, column with tasks can't be deleted.
+func (h *Handlers) DeleteColumnHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "columnID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ManageColumns); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getColumn(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := h.DB.Column.DeleteByID(r.Context(), ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getColumn - returns column with columnID if it belongs to board with boardID.
+func (h *Handlers) getColumn(ctx context.Context, boardID, columnID uint32) (database.Column, error) {
+	column, err := h.DB.Column.GetByID(ctx, columnID)
+	if err != nil {
+		return database.Column{}, err
+	}
+
+	if column.BoardID != boardID {
+		return database.Column{}, database.ErrNotFound
+	}
+	return column, nil
+}
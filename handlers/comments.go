@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// commentsPage - response with page of task comments, NextAfterID is 'after' parameter
+// for requesting next page, it's nil if there are no more comments.
+type commentsPage struct {
+	NextAfterID *uint32            `json:"next_after_id"`
+	Comments    []database.Comment `json:"comments"`
+}
+
+// GetCommentsHandler - handles getting page of task comments in order of creation, page is selected
+// by 'after' (ID of the last comment of previous page) and 'limit' query parameters.
+func (h *Handlers) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	page, err := queryPage(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getTask(r.Context(), ids[0], ids[1], database.WithoutRelations()); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	comments, err := h.DB.Comment.GetByTask(r.Context(), ids[1], page)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	resp := commentsPage{Comments: comments}
+	if len(comments) == page.Limit {
+		resp.NextAfterID = &comments[len(comments)-1].ID
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// CreateCommentHandler - handles commenting task, authenticated person becomes comment author.
+func (h *Handlers) CreateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		h.writeError(w, auth.ErrUnauthenticated)
+		return
+	}
+
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.WriteComments); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var comment database.Comment
+	if err := readJSON(w, r, &comment); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getTask(r.Context(), ids[0], ids[1], database.WithoutRelations()); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	comment.TaskID = ids[1]
+	comment.Author = database.CommentAuthor(person.Small())
+
+	comment, err = h.DB.Comment.Create(r.Context(), comment)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, comment)
+}
+
+// UpdateCommentHandler - handles editing text of comment by its author or board admin.
+func (h *Handlers) UpdateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "commentID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var update database.CommentUpdate
+	if err := readJSON(w, r, &update); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := h.authorizeComment(r, ids[0], ids[1], ids[2]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	comment, err := h.DB.Comment.Update(r.Context(), ids[2], update)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, comment)
+}
+
+// DeleteCommentHandler - handles deleting comment with its replies by comment author or board admin.
+func (h *Handlers) DeleteCommentHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "commentID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := h.authorizeComment(r, ids[0], ids[1], ids[2]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := h.DB.Comment.DeleteByID(r.Context(), ids[2]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeComment - checks that comment with commentID belongs to board task and authenticated person
+// is comment author or can manage comments of board, returns auth.ErrForbidden otherwise.
+func (h *Handlers) authorizeComment(r *http.Request, boardID, taskID, commentID uint32) error {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		return auth.ErrUnauthenticated
+	}
+
+	board, err := h.authorizeBoard(r, boardID, auth.WriteComments)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.getTask(r.Context(), boardID, taskID, database.WithoutRelations()); err != nil {
+		return err
+	}
+
+	comment, err := h.getComment(r.Context(), taskID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.Author.ID != person.ID {
+		return auth.Authorize(board, person.ID, auth.ManageComments)
+	}
+	return nil
+}
+
+// getComment - returns comment with commentID if it belongs to task with taskID.
+func (h *Handlers) getComment(ctx context.Context, taskID, commentID uint32) (database.Comment, error) {
+	comment, err := h.DB.Comment.GetByID(ctx, commentID)
+	if err != nil {
+		return database.Comment{}, err
+	}
+
+	if comment.TaskID != taskID {
+		return database.Comment{}, database.ErrNotFound
+	}
+	return comment, nil
+}
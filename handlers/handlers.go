@@ -1,16 +1,38 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/s4lat/gokan/auth"
 	"github.com/s4lat/gokan/database"
 	"github.com/s4lat/gokan/log"
 )
 
+// maxBodySize - max size of request body in bytes.
+const maxBodySize = 1 << 20
+
+// errBadRequest - wrapped by errors, caused by invalid request from client.
+var errBadRequest = errors.New("bad request")
+
 // Handlers - contains all http handlers methods.
 type Handlers struct {
-	DB  database.DB
-	Log log.Log
+	DB            database.DB
+	Log           log.Log
+	Sessions      auth.Sessions
+	SecureCookies bool // sets Secure flag of session cookie, must be true when served over HTTPS
+}
+
+// errorResponse - body of response with error.
+type errorResponse struct {
+	Error string `json:"error"`
+	Field string `json:"field,omitempty"` // invalid field of request, if error caused by it
+	Token string `json:"token,omitempty"` // offending token of filter query, if error caused by it
 }
 
 // IndexHandler - handles index page.
@@ -20,3 +42,316 @@ func (h *Handlers) IndexHandler(w http.ResponseWriter, r *http.Request) {
 		h.Log.Error(err)
 	}
 }
+
+// RegisterAPIRoutes - registers REST API handlers in root, root is expected to be '/api/v1' subrouter.
+// All routes except registration and login require authentication.
+func (h *Handlers) RegisterAPIRoutes(root *mux.Router) {
+	root.HandleFunc("/auth/register", h.RegisterHandler).Methods(http.MethodPost)
+	root.HandleFunc("/auth/login", h.LoginHandler).Methods(http.MethodPost)
+
+	r := root.NewRoute().Subrouter()
+	r.Use(h.AuthMiddleware)
+
+	r.HandleFunc("/auth/logout", h.LogoutHandler).Methods(http.MethodPost)
+	r.HandleFunc("/auth/me", h.MeHandler).Methods(http.MethodGet)
+	r.HandleFunc("/auth/password", h.ChangePasswordHandler).Methods(http.MethodPost)
+	r.HandleFunc("/auth/tokens", h.IssueTokenHandler).Methods(http.MethodPost)
+
+	r.HandleFunc("/persons/{personID:[0-9]+}", h.GetPersonHandler).Methods(http.MethodGet)
+	r.HandleFunc("/persons/{personID:[0-9]+}", h.UpdatePersonHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/persons/{personID:[0-9]+}", h.DeletePersonHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/persons/{personID:[0-9]+}/tasks/overdue",
+		h.GetOverdueTasksHandler).Methods(http.MethodGet)
+
+	r.HandleFunc("/search", h.SearchHandler).Methods(http.MethodGet)
+
+	r.HandleFunc("/trash/boards", h.GetDeletedBoardsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/trash/boards/{boardID:[0-9]+}/restore", h.RestoreBoardHandler).Methods(http.MethodPost)
+
+	r.HandleFunc("/boards", h.CreateBoardHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}", h.GetBoardHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}", h.UpdateBoardHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{boardID:[0-9]+}", h.DeleteBoardHandler).Methods(http.MethodDelete)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/archive", h.ArchiveBoardHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/unarchive", h.UnarchiveBoardHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/owner", h.TransferOwnershipHandler).Methods(http.MethodPut)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/activity", h.GetBoardActivityHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/contributors",
+		h.AddContributorHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/contributors/{personID:[0-9]+}",
+		h.SetContributorRoleHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/contributors/{personID:[0-9]+}",
+		h.RemoveContributorHandler).Methods(http.MethodDelete)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/columns", h.CreateColumnHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/columns/{columnID:[0-9]+}",
+		h.GetColumnHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/columns/{columnID:[0-9]+}",
+		h.UpdateColumnHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/columns/{columnID:[0-9]+}",
+		h.DeleteColumnHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/columns/{columnID:[0-9]+}/archive",
+		h.ArchiveColumnTasksHandler).Methods(http.MethodPost)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tags", h.CreateTagHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tags/{tagID:[0-9]+}", h.GetTagHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tags/{tagID:[0-9]+}", h.UpdateTagHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tags/{tagID:[0-9]+}", h.DeleteTagHandler).Methods(http.MethodDelete)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks", h.CreateTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks", h.GetTasksHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/due", h.GetDueTasksHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/trash", h.GetDeletedTasksHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/trash/{taskID:[0-9]+}/restore",
+		h.RestoreTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}",
+		h.GetTaskHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}",
+		h.UpdateTaskHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}",
+		h.DeleteTaskHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/move",
+		h.MoveTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/archive",
+		h.ArchiveTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/unarchive",
+		h.UnarchiveTaskHandler).Methods(http.MethodPost)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/subtasks",
+		h.AddSubtaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/subtasks/{subtaskID:[0-9]+}",
+		h.UpdateSubtaskHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/subtasks/{subtaskID:[0-9]+}",
+		h.RemoveSubtaskHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/subtasks/{subtaskID:[0-9]+}/move",
+		h.MoveSubtaskHandler).Methods(http.MethodPost)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/tags/{tagID:[0-9]+}",
+		h.AddTaskTagHandler).Methods(http.MethodPut)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/tags/{tagID:[0-9]+}",
+		h.RemoveTaskTagHandler).Methods(http.MethodDelete)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/assignees/{personID:[0-9]+}",
+		h.AddAssigneeHandler).Methods(http.MethodPut)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/assignees/{personID:[0-9]+}",
+		h.RemoveAssigneeHandler).Methods(http.MethodDelete)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/comments",
+		h.GetCommentsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/comments",
+		h.CreateCommentHandler).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/comments/{commentID:[0-9]+}",
+		h.UpdateCommentHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/comments/{commentID:[0-9]+}",
+		h.DeleteCommentHandler).Methods(http.MethodDelete)
+
+	r.HandleFunc("/boards/{boardID:[0-9]+}/tasks/{taskID:[0-9]+}/activity",
+		h.GetTaskActivityHandler).Methods(http.MethodGet)
+}
+
+// writeJSON - writes v encoded to JSON as response body with status code.
+func (h *Handlers) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.Log.Error(err)
+	}
+}
+
+// writeError - writes error response, status code is chosen by type of err.
+func (h *Handlers) writeError(w http.ResponseWriter, err error) {
+	var (
+		dbErr         *database.Error
+		queryErr      *database.QueryError
+		validationErr auth.ValidationError
+	)
+
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		h.writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+	case errors.Is(err, errBadRequest):
+		h.writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+	case errors.As(err, &queryErr):
+		h.writeJSON(w, http.StatusBadRequest,
+			errorResponse{Error: queryErr.Error(), Field: "filter", Token: queryErr.Token})
+	case errors.As(err, &validationErr):
+		h.writeJSON(w, http.StatusBadRequest, errorResponse{Error: validationErr.Error(), Field: validationErr.Field})
+	case errors.Is(err, auth.ErrUnauthenticated):
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		h.writeJSON(w, http.StatusUnauthorized, errorResponse{Error: err.Error()})
+	case errors.Is(err, database.ErrForbidden):
+		h.writeJSON(w, http.StatusForbidden, errorResponse{Error: err.Error()})
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		h.writeJSON(w, http.StatusUnauthorized, errorResponse{Error: err.Error()})
+	case errors.Is(err, auth.ErrUsernameTaken):
+		h.writeJSON(w, http.StatusConflict, errorResponse{Error: err.Error(), Field: "username"})
+	case errors.Is(err, auth.ErrEmailTaken):
+		h.writeJSON(w, http.StatusConflict, errorResponse{Error: err.Error(), Field: "email"})
+	case errors.As(err, &dbErr) && errors.Is(dbErr, database.ErrConflict):
+		h.writeJSON(w, http.StatusConflict, errorResponse{Error: dbErr.Message})
+	case errors.As(err, &dbErr) && errors.Is(dbErr, database.ErrInvalidInput):
+		h.writeJSON(w, http.StatusBadRequest, errorResponse{Error: dbErr.Message})
+	default:
+		h.Log.Error(err)
+		h.writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "internal server error"})
+	}
+}
+
+// authorizeBoard - returns board with boardID if authenticated person has permission in it,
+// returns auth.ErrForbidden otherwise. Board is loaded with contributors and data selected by opts.
+// Returns database.ErrNotFound to outsiders of board, so they can't find out which board IDs exist.
+func (h *Handlers) authorizeBoard(r *http.Request, boardID uint32,
+	permission auth.Permission, opts ...database.LoadOption) (database.Board, error) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		return database.Board{}, auth.ErrUnauthenticated
+	}
+
+	opts = append(opts, database.WithContributors())
+	board, err := h.DB.Board.GetByID(r.Context(), boardID, opts...)
+	if err != nil {
+		return database.Board{}, err
+	}
+
+	if auth.RoleOf(board, person.ID) == auth.RoleOutsider {
+		return database.Board{}, database.ErrNotFound
+	}
+
+	if err := auth.Authorize(board, person.ID, permission); err != nil {
+		return database.Board{}, err
+	}
+	return board, nil
+}
+
+// readJSON - decodes JSON request body to v.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid JSON body: %v", errBadRequest, err)
+	}
+	return nil
+}
+
+// pathID - returns ID from route variable with name.
+func pathID(r *http.Request, name string) (uint32, error) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s", errBadRequest, name)
+	}
+	return uint32(id), nil
+}
+
+// queryTime - returns time in RFC 3339 format from query parameter with name, or def if parameter is empty.
+func queryTime(r *http.Request, name string, def time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid %s, expected RFC 3339 time", errBadRequest, name)
+	}
+	return t, nil
+}
+
+// queryID - returns ID from query parameter with name, or 0 if parameter is empty.
+func queryID(r *http.Request, name string) (uint32, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s", errBadRequest, name)
+	}
+	return uint32(id), nil
+}
+
+// queryInt - returns integer from query parameter with name, or def if parameter is empty.
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s, expected integer", errBadRequest, name)
+	}
+	return i, nil
+}
+
+// queryBool - returns boolean from query parameter with name, or false if parameter is empty.
+func queryBool(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid %s, expected boolean", errBadRequest, name)
+	}
+	return b, nil
+}
+
+// queryPage - returns database.Page selected by 'after' (ID of the last row of previous page)
+// and 'limit' query parameters.
+func queryPage(r *http.Request) (database.Page, error) {
+	var page database.Page
+	var err error
+	if page.AfterID, err = queryID(r, "after"); err != nil {
+		return database.Page{}, err
+	}
+
+	if page.Limit, err = queryLimit(r); err != nil {
+		return database.Page{}, err
+	}
+	return page, nil
+}
+
+// queryLimit - returns count of rows in page from 'limit' query parameter, or database.DefaultPageLimit
+// if parameter is empty.
+func queryLimit(r *http.Request) (int, error) {
+	limit, err := queryInt(r, "limit", database.DefaultPageLimit)
+	if err != nil {
+		return 0, err
+	}
+	if limit < 1 || limit > database.MaxPageLimit {
+		return 0, fmt.Errorf("%w: limit must be in range [1, %d]", errBadRequest, database.MaxPageLimit)
+	}
+	return limit, nil
+}
+
+// queryFloat - sets dst to number from query parameter with name, dst is left unchanged if parameter is empty.
+func queryFloat(r *http.Request, name string, dst **float64) error {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid %s, expected number", errBadRequest, name)
+	}
+	*dst = &f
+	return nil
+}
+
+// pathIDs - returns IDs from route variables with names in the same order.
+func pathIDs(r *http.Request, names ...string) ([]uint32, error) {
+	ids := make([]uint32, 0, len(names))
+	for _, name := range names {
+		id, err := pathID(r, name)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
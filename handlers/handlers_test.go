@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+	"github.com/s4lat/gokan/log"
+)
+
+// Persons of test board, each one authenticates with bearer token equal to its name.
+const (
+	ownerID uint32 = iota + 1
+	adminID
+	memberID
+	viewerID
+	outsiderID
+)
+
+var testTokens = map[string]uint32{
+	"owner":    ownerID,
+	"admin":    adminID,
+	"member":   memberID,
+	"viewer":   viewerID,
+	"outsider": outsiderID,
+}
+
+// testBoard - the only board in fake db, its column and task, other IDs belong to board 2.
+var testBoard = database.Board{
+	ID:    1,
+	Name:  "board",
+	Owner: database.BoardOwner{ID: ownerID, Email: "person1@mail.com"},
+	Contributors: []database.Contributor{
+		{ID: adminID, Email: "person2@mail.com", Role: database.ContributorAdmin},
+		{ID: memberID, Email: "person3@mail.com", Role: database.ContributorMember},
+		{ID: viewerID, Email: "person4@mail.com", Role: database.ContributorViewer},
+	},
+	Tasks: []database.Task{{
+		ID:        testTaskID,
+		Author:    database.TaskAuthor{ID: memberID, Email: "person3@mail.com"},
+		Assignees: []database.TaskAssignee{{ID: adminID, Email: "person2@mail.com"}},
+	}},
+}
+
+const (
+	testColumnID uint32 = 5
+	testTaskID   uint32 = 7
+)
+
+// fakeSessions - database.SessionManager with sessions of testTokens, other methods panic.
+type fakeSessions struct {
+	database.SessionManager
+}
+
+func (fakeSessions) GetActiveByTokenHash(_ context.Context, tokenHash string) (database.Session, error) {
+	for token, personID := range testTokens {
+		if auth.HashToken(token) == tokenHash {
+			return database.Session{Kind: database.SessionToken, PersonID: personID}, nil
+		}
+	}
+	return database.Session{}, database.ErrNotFound
+}
+
+// fakePersons - database.PersonManager with persons of testTokens, other methods panic.
+type fakePersons struct {
+	database.PersonManager
+}
+
+func (fakePersons) GetByID(_ context.Context, personID uint32, _ ...database.LoadOption) (database.Person, error) {
+	if personID == 0 || personID > outsiderID {
+		return database.Person{}, database.ErrNotFound
+	}
+	return database.Person{ID: personID, Username: fmt.Sprintf("person%d", personID),
+		Email: fmt.Sprintf("person%d@mail.com", personID)}, nil
+}
+
+func (fp fakePersons) GetSmallByID(ctx context.Context, personID uint32) (database.SmallPerson, error) {
+	person, err := fp.GetByID(ctx, personID)
+	return person.Small(), err
+}
+
+// fakeBoards - database.BoardManager with testBoard, other methods panic.
+type fakeBoards struct {
+	database.BoardManager
+}
+
+func (fakeBoards) GetByID(_ context.Context, boardID uint32, _ ...database.LoadOption) (database.Board, error) {
+	if boardID != testBoard.ID {
+		return database.Board{}, database.ErrNotFound
+	}
+	return testBoard, nil
+}
+
+func (fb fakeBoards) Update(ctx context.Context, boardID uint32, update database.BoardUpdate) (database.Board, error) {
+	board, err := fb.GetByID(ctx, boardID)
+	if update.Name != nil {
+		board.Name = *update.Name
+	}
+	return board, err
+}
+
+func (fb fakeBoards) DeleteByID(ctx context.Context, boardID uint32) error {
+	_, err := fb.GetByID(ctx, boardID)
+	return err
+}
+
+// fakeColumns - database.ColumnManager with column testColumnID of testBoard, other methods panic.
+type fakeColumns struct {
+	database.ColumnManager
+}
+
+func (fakeColumns) GetByID(_ context.Context, columnID uint32, _ ...database.LoadOption) (database.Column, error) {
+	if columnID == testColumnID {
+		return database.Column{ID: columnID, BoardID: testBoard.ID}, nil
+	}
+	return database.Column{ID: columnID, BoardID: testBoard.ID + 1}, nil
+}
+
+func (fakeColumns) DeleteByID(context.Context, uint32) error {
+	return nil
+}
+
+// fakeTasks - database.TaskManager with task testTaskID of testBoard, other methods panic.
+type fakeTasks struct {
+	database.TaskManager
+}
+
+func (fakeTasks) GetByID(_ context.Context, taskID uint32, _ ...database.LoadOption) (database.Task, error) {
+	if taskID == testTaskID {
+		return database.Task{ID: taskID, BoardID: testBoard.ID}, nil
+	}
+	return database.Task{ID: taskID, BoardID: testBoard.ID + 1}, nil
+}
+
+// newTestRouter - returns API routes served by Handlers with fake db.
+func newTestRouter() *mux.Router {
+	db := database.DB{
+		Person:  fakePersons{},
+		Board:   fakeBoards{},
+		Column:  fakeColumns{},
+		Task:    fakeTasks{},
+		Session: fakeSessions{},
+	}
+	h := &Handlers{
+		DB:       db,
+		Log:      log.NewLogger(io.Discard),
+		Sessions: auth.Sessions{Store: db.Session, Persons: db.Person},
+	}
+
+	r := mux.NewRouter()
+	h.RegisterAPIRoutes(r)
+	return r
+}
+
+func TestWriteError(t *testing.T) {
+	h := &Handlers{Log: log.NewLogger(io.Discard)}
+
+	cases := map[string]struct {
+		err    error
+		field  string
+		status int
+	}{
+		"not found":       {fmt.Errorf("GetByID() -> %w", database.ErrNotFound), "", http.StatusNotFound},
+		"invalid input":   {&database.Error{Kind: database.ErrInvalidInput}, "", http.StatusBadRequest},
+		"query":           {&database.QueryError{Token: "due:"}, "filter", http.StatusBadRequest},
+		"bad request":     {fmt.Errorf("%w: invalid boardID", errBadRequest), "", http.StatusBadRequest},
+		"validation":      {auth.ValidationError{Field: "email"}, "email", http.StatusBadRequest},
+		"forbidden":       {fmt.Errorf("%w: no permission", database.ErrForbidden), "", http.StatusForbidden},
+		"conflict":        {&database.Error{Kind: database.ErrConflict}, "", http.StatusConflict},
+		"username taken":  {auth.ErrUsernameTaken, "username", http.StatusConflict},
+		"unauthenticated": {auth.ErrUnauthenticated, "", http.StatusUnauthorized},
+		"internal":        {errors.New("connection refused"), "", http.StatusInternalServerError},
+	}
+	for name, c := range cases {
+		w := httptest.NewRecorder()
+		h.writeError(w, c.err)
+
+		var resp errorResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if w.Code != c.status || resp.Field != c.field {
+			t.Errorf("%s: writeError() responded %d with field %q, expected %d with field %q",
+				name, w.Code, resp.Field, c.status, c.field)
+		}
+	}
+}
+
+func TestRoutesAuthorization(t *testing.T) {
+	r := newTestRouter()
+
+	cases := []struct {
+		token  string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		// unauthenticated
+		{"", http.MethodGet, "/boards/1", "", http.StatusUnauthorized},
+		{"forged", http.MethodGet, "/boards/1", "", http.StatusUnauthorized},
+
+		// board
+		{"viewer", http.MethodGet, "/boards/1", "", http.StatusOK},
+		{"outsider", http.MethodGet, "/boards/1", "", http.StatusNotFound},
+		{"owner", http.MethodGet, "/boards/2", "", http.StatusNotFound},
+		{"viewer", http.MethodGet, "/boards/1?filter=due:someday", "", http.StatusBadRequest},
+		{"member", http.MethodPatch, "/boards/1", `{"board_name": "new"}`, http.StatusForbidden},
+		{"admin", http.MethodPatch, "/boards/1", `{"board_name": "new"}`, http.StatusOK},
+		{"admin", http.MethodDelete, "/boards/1", "", http.StatusForbidden},
+		{"owner", http.MethodDelete, "/boards/1", "", http.StatusNoContent},
+
+		// column
+		{"viewer", http.MethodDelete, "/boards/1/columns/5", "", http.StatusForbidden},
+		{"member", http.MethodDelete, "/boards/1/columns/5", "", http.StatusNoContent},
+		{"member", http.MethodDelete, "/boards/1/columns/6", "", http.StatusNotFound},
+
+		// task
+		{"viewer", http.MethodPost, "/boards/1/tasks", `{"task_name": "task"}`, http.StatusForbidden},
+		{"viewer", http.MethodGet, "/boards/1/tasks/7", "", http.StatusOK},
+		{"viewer", http.MethodGet, "/boards/1/tasks/8", "", http.StatusNotFound},
+		{"outsider", http.MethodGet, "/boards/1/tasks/7", "", http.StatusNotFound},
+		{"outsider", http.MethodDelete, "/boards/1/tasks/7", "", http.StatusNotFound},
+
+		// contributor
+		{"member", http.MethodPost, "/boards/1/contributors", `{"person_id": 5}`, http.StatusForbidden},
+		{"member", http.MethodPatch, "/boards/1/contributors/4", `{"role": "admin"}`, http.StatusForbidden},
+		{"viewer", http.MethodDelete, "/boards/1/contributors/3", "", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != c.status {
+			t.Errorf("%s %s by %q responded %d, expected %d: %s", c.method, c.path, c.token, w.Code, c.status,
+				w.Body.String())
+		}
+	}
+}
+
+func TestGetPersonHandler(t *testing.T) {
+	r := newTestRouter()
+
+	for token, expectedEmail := range map[string]string{"owner": "person1@mail.com", "viewer": ""} {
+		req := httptest.NewRequest(http.MethodGet, "/persons/1", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var person personView
+		if err := json.NewDecoder(w.Body).Decode(&person); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusOK || person.ID != ownerID || person.Email != expectedEmail {
+			t.Errorf("GET /persons/1 by %q responded %d with %+v, expected email %q",
+				token, w.Code, person, expectedEmail)
+		}
+	}
+}
+
+func TestGetBoardHandlerHidesEmails(t *testing.T) {
+	r := newTestRouter()
+
+	for token := range testTokens {
+		if token == "outsider" {
+			continue
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/boards/1", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "@mail.com") {
+			t.Errorf("GET /boards/1 by %q responded %d with %s, expected no emails", token, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// sessionCookieName - name of cookie with signed session token.
+const sessionCookieName = "gokan_session"
+
+// ctxKey - type of keys for values put in request context by handlers.
+type ctxKey int
+
+const (
+	personCtxKey ctxKey = iota
+	sessionCtxKey
+)
+
+// AuthMiddleware - authenticates request by 'Authorization: Bearer' token or session cookie
+// and puts authenticated person into request context, responds 401 to anonymous requests.
+// Authenticated person is also set as actor of changes made in db, see database.WithActor.
+func (h *Handlers) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		person, session, err := h.authenticate(r)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		ctx := database.WithActor(r.Context(), person.ID)
+		ctx = context.WithValue(ctx, personCtxKey, person)
+		ctx = context.WithValue(ctx, sessionCtxKey, session)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// PersonFromContext - returns person authenticated by AuthMiddleware.
+func PersonFromContext(ctx context.Context) (database.Person, bool) {
+	person, ok := ctx.Value(personCtxKey).(database.Person)
+	return person, ok
+}
+
+// sessionFromContext - returns session of person authenticated by AuthMiddleware.
+func sessionFromContext(ctx context.Context) (database.Session, bool) {
+	session, ok := ctx.Value(sessionCtxKey).(database.Session)
+	return session, ok
+}
+
+// authenticate - checks bearer token if Authorization header is set, session cookie otherwise.
+func (h *Handlers) authenticate(r *http.Request) (database.Person, database.Session, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") {
+			return database.Person{}, database.Session{}, auth.ErrUnauthenticated
+		}
+		return h.Sessions.AuthenticateToken(r.Context(), strings.TrimSpace(token))
+	}
+
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return database.Person{}, database.Session{}, auth.ErrUnauthenticated
+	}
+	return h.Sessions.AuthenticateCookie(r.Context(), cookie.Value)
+}
+
+// setSessionCookie - sets session cookie, cookie with empty value and zero session removes it.
+func (h *Handlers) setSessionCookie(w http.ResponseWriter, value string, session database.Session) {
+	cookie := &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    value,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		Secure:   h.SecureCookies,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if value == "" {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// personView - representation of database.Person in responses to person themselves, without password hash.
+type personView struct {
+	database.SmallPerson
+	Email          string                `json:"email"`
+	Boards         []database.SmallBoard `json:"boards"`
+	ArchivedBoards []database.SmallBoard `json:"archived_boards"`
+	AssignedTasks  []database.Task       `json:"assigned_tasks"`
+}
+
+// newPersonView - returns personView of person.
+func newPersonView(person database.Person) personView {
+	return personView{
+		SmallPerson:    person.Small(),
+		Email:          person.Email,
+		Boards:         person.Boards,
+		ArchivedBoards: person.ArchivedBoards,
+		AssignedTasks:  person.AssignedTasks,
+	}
+}
+
+// deletePersonRequest - optional body of person deletion request.
+type deletePersonRequest struct {
+	Successors map[uint32]uint32 `json:"successors"` // new owners of person boards by board ID
+}
+
+// GetPersonHandler - handles getting person by ID, email, boards and assigned tasks
+// are shown only to the person themselves.
+func (h *Handlers) GetPersonHandler(w http.ResponseWriter, r *http.Request) {
+	personID, err := pathID(r, "personID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := authorizePerson(r, personID); err != nil {
+		person, err := h.DB.Person.GetSmallByID(r.Context(), personID)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		h.writeJSON(w, http.StatusOK, person)
+		return
+	}
+
+	person, err := h.DB.Person.GetByID(r.Context(), personID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newPersonView(person))
+}
+
+// UpdatePersonHandler - handles partial update of person.
+func (h *Handlers) UpdatePersonHandler(w http.ResponseWriter, r *http.Request) {
+	personID, err := pathID(r, "personID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := authorizePerson(r, personID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var update database.PersonUpdate
+	if err := readJSON(w, r, &update); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	person, err := auth.UpdatePerson(r.Context(), h.DB.Person, personID, update)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newPersonView(person))
+}
+
+// DeletePersonHandler - handles deletion of person, every owned board with contributors must have
+// successor among its contributors in request body, other boards may have any person as successor.
+func (h *Handlers) DeletePersonHandler(w http.ResponseWriter, r *http.Request) {
+	personID, err := pathID(r, "personID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := authorizePerson(r, personID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var req deletePersonRequest
+	if r.ContentLength != 0 {
+		if err := readJSON(w, r, &req); err != nil {
+			h.writeError(w, err)
+			return
+		}
+	}
+
+	if err := h.DB.Person.DeleteByID(r.Context(), personID, req.Successors); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetOverdueTasksHandler - handles getting overdue tasks assigned to person, only person themselves
+// can get their overdue tasks.
+func (h *Handlers) GetOverdueTasksHandler(w http.ResponseWriter, r *http.Request) {
+	personID, err := pathID(r, "personID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := authorizePerson(r, personID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	tasks, err := h.DB.Task.GetOverdueByAssignee(r.Context(), personID, time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+// authorizePerson - returns auth.ErrForbidden if authenticated person is not person with personID.
+func authorizePerson(r *http.Request, personID uint32) error {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		return auth.ErrUnauthenticated
+	}
+
+	if person.ID != personID {
+		return fmt.Errorf("%w: can't change other person", auth.ErrForbidden)
+	}
+	return nil
+}
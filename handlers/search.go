@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// SearchHandler - handles full-text search of tasks on boards of authenticated person by 'q' query parameter,
+// results are filtered by 'board', 'tag', 'assignee' and 'author' IDs, archived tasks are included if
+// 'archived' parameter is true. Results are paginated by 'limit' and 'offset' parameters.
+func (h *Handlers) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		h.writeError(w, auth.ErrUnauthenticated)
+		return
+	}
+
+	query, err := searchQuery(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	query.PersonID = person.ID
+
+	results, err := h.DB.Search.Search(r.Context(), query)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, results)
+}
+
+// searchQuery - returns database.SearchQuery from query parameters of SearchHandler, without PersonID.
+func searchQuery(r *http.Request) (database.SearchQuery, error) {
+	query := database.SearchQuery{Text: r.URL.Query().Get("q")}
+	if query.Text == "" {
+		return database.SearchQuery{}, fmt.Errorf("%w: q is required", errBadRequest)
+	}
+
+	var err error
+	filters := map[string]*uint32{
+		"board":    &query.BoardID,
+		"tag":      &query.TagID,
+		"assignee": &query.AssigneeID,
+		"author":   &query.AuthorID,
+	}
+	for name, dst := range filters {
+		if *dst, err = queryID(r, name); err != nil {
+			return database.SearchQuery{}, err
+		}
+	}
+
+	if query.IncludeArchived, err = queryBool(r, "archived"); err != nil {
+		return database.SearchQuery{}, err
+	}
+
+	if query.Limit, err = queryLimit(r); err != nil {
+		return database.SearchQuery{}, err
+	}
+
+	if query.Offset, err = queryInt(r, "offset", 0); err != nil {
+		return database.SearchQuery{}, err
+	}
+	if query.Offset < 0 {
+		return database.SearchQuery{}, fmt.Errorf("%w: offset must not be negative", errBadRequest)
+	}
+	return query, nil
+}
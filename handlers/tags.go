@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// CreateTagHandler - handles creation of tag in board.
+func (h *Handlers) CreateTagHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.ManageTags); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var tag database.Tag
+	if err := readJSON(w, r, &tag); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	tag.BoardID = boardID
+
+	tag, err = h.DB.Tag.Create(r.Context(), tag)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, tag)
+}
+
+// GetTagHandler - handles getting board tag by ID.
+func (h *Handlers) GetTagHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "tagID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	tag, err := h.getTag(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tag)
+}
+
+// UpdateTagHandler - handles partial update of board tag.
+func (h *Handlers) UpdateTagHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "tagID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ManageTags); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var update database.TagUpdate
+	if err := readJSON(w, r, &update); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getTag(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	tag, err := h.DB.Tag.Update(r.Context(), ids[1], update)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tag)
+}
+
+// DeleteTagHandler - handles deletion of board tag.
+func (h *Handlers) DeleteTagHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "tagID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ManageTags); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getTag(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := h.DB.Tag.DeleteByID(r.Context(), ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getTag - returns tag with tagID if it belongs to board with boardID.
+func (h *Handlers) getTag(ctx context.Context, boardID, tagID uint32) (database.Tag, error) {
+	tag, err := h.DB.Tag.GetByID(ctx, tagID)
+	if err != nil {
+		return database.Tag{}, err
+	}
+
+	if tag.BoardID != boardID {
+		return database.Tag{}, database.ErrNotFound
+	}
+	return tag, nil
+}
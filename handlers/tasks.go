@@ -0,0 +1,604 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/s4lat/gokan/auth"
+	"github.com/s4lat/gokan/database"
+)
+
+// moveTaskRequest - body of request for moving task.
+type moveTaskRequest struct {
+	ColumnID uint32 `json:"column_id"`
+	Position int    `json:"position"`
+}
+
+// moveSubtaskRequest - body of request for moving subtask in checklist of task.
+type moveSubtaskRequest struct {
+	Position int `json:"position"`
+}
+
+// CreateTaskHandler - handles creation of task in board, authenticated person becomes task author.
+// Tags, assignees and subtasks from request are added to task in the same transaction.
+func (h *Handlers) CreateTaskHandler(w http.ResponseWriter, r *http.Request) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		h.writeError(w, auth.ErrUnauthenticated)
+		return
+	}
+
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.authorizeBoard(r, boardID, auth.EditTasks, database.WithTags())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var task database.Task
+	if err := readJSON(w, r, &task); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	task.BoardID = boardID
+	task.Author = database.TaskAuthor(person.Small())
+
+	err = h.DB.WithTx(r.Context(), func(tx database.DB) error {
+		createdTask, err := tx.Task.Create(r.Context(), task)
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range task.Tags {
+			if !hasTag(board, tag.ID) {
+				return fmt.Errorf("%w: tag %d doesn't belong to board", errBadRequest, tag.ID)
+			}
+			if createdTask, err = tx.Task.AddTagToTask(r.Context(), tag, createdTask); err != nil {
+				return err
+			}
+		}
+
+		for _, assignee := range task.Assignees {
+			if auth.RoleOf(board, assignee.ID) == auth.RoleOutsider {
+				return fmt.Errorf("%w: assignee must be board owner or contributor", errBadRequest)
+			}
+			if createdTask, err = tx.Task.AddAssigneeToTask(r.Context(), assignee, createdTask); err != nil {
+				return err
+			}
+		}
+
+		for _, subtask := range task.Subtasks {
+			if err := checkSubtaskAssignee(board, subtask.AssigneeID); err != nil {
+				return err
+			}
+			if createdTask, err = tx.Task.AddSubtaskToTask(r.Context(), subtask, createdTask); err != nil {
+				return err
+			}
+		}
+
+		task = createdTask
+		return nil
+	})
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, task)
+}
+
+// GetTaskHandler - handles getting board task by ID.
+func (h *Handlers) GetTaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// GetTasksHandler - handles getting board tasks filtered by query parameters: 'column' ID,
+// 'priority' (can be repeated), 'min_estimate' and 'max_estimate', ordered by 'sort' parameter.
+// Archived tasks are included if 'archived' parameter is true.
+func (h *Handlers) GetTasksHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	filter := database.TaskFilter{BoardID: boardID, Sort: database.TaskSort(r.URL.Query().Get("sort"))}
+	for _, priority := range r.URL.Query()["priority"] {
+		filter.Priorities = append(filter.Priorities, database.TaskPriority(priority))
+	}
+
+	if filter.ColumnID, err = queryID(r, "column"); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := queryFloat(r, "min_estimate", &filter.MinEstimate); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := queryFloat(r, "max_estimate", &filter.MaxEstimate); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if filter.IncludeArchived, err = queryBool(r, "archived"); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	tasks, err := h.DB.Task.GetByFilter(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+// GetDueTasksHandler - handles getting board tasks with due date in range from 'from' to 'to' query
+// parameters in RFC 3339 format, range is [now, now + 7 days) by default.
+func (h *Handlers) GetDueTasksHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	from, err := queryTime(r, "from", time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	to, err := queryTime(r, "to", from.Add(7*24*time.Hour))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	tasks, err := h.DB.Task.GetDueBetween(r.Context(), boardID, from, to)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+// UpdateTaskHandler - handles partial update of board task.
+func (h *Handlers) UpdateTaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var update database.TaskUpdate
+	if err := readJSON(w, r, &update); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getTask(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.DB.Task.Update(r.Context(), ids[1], update)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// DeleteTaskHandler - handles deletion of board task.
+func (h *Handlers) DeleteTaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.getTask(r.Context(), ids[0], ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := h.DB.Task.DeleteByID(r.Context(), ids[1]); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MoveTaskHandler - handles moving task to other column or position.
+func (h *Handlers) MoveTaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var req moveTaskRequest
+	if err := readJSON(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	column, err := h.getColumn(r.Context(), ids[0], req.ColumnID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.Move(r.Context(), task, column, req.Position)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// AddSubtaskHandler - handles adding subtask to task.
+func (h *Handlers) AddSubtaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.authorizeBoard(r, ids[0], auth.EditTasks)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var subtask database.Subtask
+	if err := readJSON(w, r, &subtask); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := checkSubtaskAssignee(board, subtask.AssigneeID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.AddSubtaskToTask(r.Context(), subtask, task)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, task)
+}
+
+// UpdateSubtaskHandler - handles partial update of task subtask, e.g. marking it done.
+func (h *Handlers) UpdateSubtaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "subtaskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.authorizeBoard(r, ids[0], auth.EditTasks)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var update database.SubtaskUpdate
+	if err := readJSON(w, r, &update); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if err := checkSubtaskAssignee(board, update.AssigneeID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.UpdateSubtask(r.Context(), database.Subtask{ID: ids[2]}, task, update)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// MoveSubtaskHandler - handles moving subtask to other position in checklist of task.
+func (h *Handlers) MoveSubtaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "subtaskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var req moveSubtaskRequest
+	if err := readJSON(w, r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.MoveSubtask(r.Context(), database.Subtask{ID: ids[2]}, task, req.Position)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// RemoveSubtaskHandler - handles removing subtask from task.
+func (h *Handlers) RemoveSubtaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "subtaskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.RemoveSubtaskFromTask(r.Context(), database.Subtask{ID: ids[2]}, task)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// AddTaskTagHandler - handles adding board tag to task.
+func (h *Handlers) AddTaskTagHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "tagID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	tag, err := h.getTag(r.Context(), ids[0], ids[2])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.AddTagToTask(r.Context(), tag, task)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// RemoveTaskTagHandler - handles removing tag from task.
+func (h *Handlers) RemoveTaskTagHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "tagID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.RemoveTagFromTask(r.Context(), database.Tag{ID: ids[2]}, task)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// AddAssigneeHandler - handles assigning person to task.
+func (h *Handlers) AddAssigneeHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "personID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.authorizeBoard(r, ids[0], auth.EditTasks)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if auth.RoleOf(board, ids[2]) == auth.RoleOutsider {
+		h.writeError(w, fmt.Errorf("%w: assignee must be board owner or contributor", errBadRequest))
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	person, err := h.DB.Person.GetSmallByID(r.Context(), ids[2])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.AddAssigneeToTask(r.Context(), database.TaskAssignee(person), task)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// RemoveAssigneeHandler - handles unassigning person from task.
+func (h *Handlers) RemoveAssigneeHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID", "personID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.getTask(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err = h.DB.Task.RemoveAssignFromTask(r.Context(), database.TaskAssignee{ID: ids[2]}, task)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, task)
+}
+
+// getTask - returns task with taskID loaded with data selected by opts, if it belongs to board with boardID.
+func (h *Handlers) getTask(ctx context.Context, boardID, taskID uint32,
+	opts ...database.LoadOption) (database.Task, error) {
+	task, err := h.DB.Task.GetByID(ctx, taskID, opts...)
+	if err != nil {
+		return database.Task{}, err
+	}
+
+	if task.BoardID != boardID {
+		return database.Task{}, database.ErrNotFound
+	}
+	return task, nil
+}
+
+// hasTag - checks if board has tag with tagID.
+func hasTag(board database.Board, tagID uint32) bool {
+	for _, tag := range board.Tags {
+		if tag.ID == tagID {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSubtaskAssignee - returns error if assigneeID is set and isn't board owner or contributor.
+func checkSubtaskAssignee(board database.Board, assigneeID *uint32) error {
+	if assigneeID != nil && auth.RoleOf(board, *assigneeID) == auth.RoleOutsider {
+		return fmt.Errorf("%w: subtask assignee must be board owner or contributor", errBadRequest)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/s4lat/gokan/auth"
+)
+
+// GetDeletedBoardsHandler - handles getting boards in trash, owned by authenticated person.
+func (h *Handlers) GetDeletedBoardsHandler(w http.ResponseWriter, r *http.Request) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		h.writeError(w, auth.ErrUnauthenticated)
+		return
+	}
+
+	boards, err := h.DB.Board.GetDeleted(r.Context(), person.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, boards)
+}
+
+// RestoreBoardHandler - handles restoring board from trash, only board owner can restore it,
+// the same way only owner can delete board.
+func (h *Handlers) RestoreBoardHandler(w http.ResponseWriter, r *http.Request) {
+	person, ok := PersonFromContext(r.Context())
+	if !ok {
+		h.writeError(w, auth.ErrUnauthenticated)
+		return
+	}
+
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	board, err := h.DB.Board.Restore(r.Context(), boardID, person.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, board)
+}
+
+// GetDeletedTasksHandler - handles getting tasks of board, that are in trash.
+func (h *Handlers) GetDeletedTasksHandler(w http.ResponseWriter, r *http.Request) {
+	boardID, err := pathID(r, "boardID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, boardID, auth.ReadBoard); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	tasks, err := h.DB.Task.GetDeleted(r.Context(), boardID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+// RestoreTaskHandler - handles restoring task of board from trash, restored task is placed
+// at the end of its column.
+func (h *Handlers) RestoreTaskHandler(w http.ResponseWriter, r *http.Request) {
+	ids, err := pathIDs(r, "boardID", "taskID")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if _, err := h.authorizeBoard(r, ids[0], auth.EditTasks); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	task, err := h.DB.Task.Restore(r.Context(), ids[0], ids[1])
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, task)
+}
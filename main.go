@@ -2,15 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/s4lat/gokan/auth"
 	"github.com/s4lat/gokan/database"
 	"github.com/s4lat/gokan/handlers"
 	"github.com/s4lat/gokan/log"
@@ -48,16 +51,56 @@ func main() {
 	}
 	db := database.NewDB(dbPool)
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(context.Background(), db, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if os.Getenv("RECREATE_DB") == "1" {
 		if err := db.System.RecreateAllTables(context.Background()); err != nil {
 			logger.Fatal(err)
 		}
 	}
 
+	if os.Getenv("MIGRATE_DB") == "1" {
+		applied, err := db.System.MigrateUp(context.Background())
+		if err != nil {
+			logger.Fatal(err)
+		}
+		logger.Info(fmt.Sprintf("Applied %d migrations", applied))
+	}
+
+	// [STARTING TRASH PURGE AND SESSION CLEANUP]
+	trashRetention := defaultTrashRetention
+	if value := os.Getenv("GOKAN_TRASH_RETENTION"); value != "" {
+		trashRetention, err = time.ParseDuration(value)
+		if err != nil || trashRetention < 0 {
+			logger.Fatal("Environment variable 'GOKAN_TRASH_RETENTION' must be non-negative duration, e.g. 720h")
+		}
+	}
+	go purgeTrash(context.Background(), db, logger, trashRetention)
+	go deleteExpiredSessions(context.Background(), db, logger)
+
+	// [INITIALIZING SESSIONS]
+	signer, err := auth.NewSigner(os.Getenv("GOKAN_SECRET"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+	sessions := auth.Sessions{Store: db.Session, Persons: db.Person, Signer: signer}
+
 	// [INITIALIZING HANDLERS AND SERVER]
-	h := handlers.Handlers{DB: db, Log: logger}
+	h := handlers.Handlers{
+		DB:            db,
+		Log:           logger,
+		Sessions:      sessions,
+		SecureCookies: os.Getenv("GO_ENV") != "development",
+	}
 	r := mux.NewRouter()
 	r.HandleFunc("/", h.IndexHandler)
+	h.RegisterAPIRoutes(r.PathPrefix("/api/v1").Subrouter())
 
 	s := http.Server{
 		Addr:         os.Getenv("GOKAN_ADDR"),
@@ -72,3 +115,105 @@ func main() {
 		logger.Fatal(err)
 	}
 }
+
+const (
+	// defaultTrashRetention - how long deleted boards and tasks are kept in trash, if GOKAN_TRASH_RETENTION isn't set.
+	defaultTrashRetention = 30 * 24 * time.Hour
+	// trashPurgeInterval - how often boards and tasks with expired retention are purged from trash.
+	trashPurgeInterval = time.Hour
+	// sessionCleanupInterval - how often expired and revoked sessions are deleted.
+	sessionCleanupInterval = time.Hour
+)
+
+// purgeTrash - permanently deletes boards and tasks, that are in trash longer than retention,
+// every trashPurgeInterval until ctx is done.
+func purgeTrash(ctx context.Context, db database.DB, logger log.Log, retention time.Duration) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		deletedBefore := time.Now().Add(-retention)
+
+		boards, err := db.Board.PurgeDeleted(ctx, deletedBefore)
+		if err != nil {
+			logger.Error(err)
+		}
+		tasks, err := db.Task.PurgeDeleted(ctx, deletedBefore)
+		if err != nil {
+			logger.Error(err)
+		}
+		if boards+tasks > 0 {
+			logger.Info(fmt.Sprintf("Purged %d boards and %d tasks from trash", boards, tasks))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteExpiredSessions - deletes expired and revoked sessions every sessionCleanupInterval until ctx is done.
+func deleteExpiredSessions(ctx context.Context, db database.DB, logger log.Log) {
+	ticker := time.NewTicker(sessionCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		sessions, err := db.Session.DeleteExpired(ctx)
+		if err != nil {
+			logger.Error(err)
+		}
+		if sessions > 0 {
+			logger.Info(fmt.Sprintf("Deleted %d expired sessions", sessions))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// migrate - handles 'gokan migrate up|down [steps]|version' command.
+func migrate(ctx context.Context, db database.DB, args []string) error {
+	const usage = "usage: gokan migrate up|down [steps]|version"
+
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := db.System.MigrateUp(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migrations\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return errors.New(usage)
+			}
+			steps = n
+		}
+
+		reverted, err := db.System.MigrateDown(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %d migrations\n", reverted)
+	case "version":
+		version, err := db.System.SchemaVersion(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Schema version: %d\n", version)
+	default:
+		return errors.New(usage)
+	}
+	return nil
+}